@@ -0,0 +1,267 @@
+// Package act generates acceptance acts of certificates on client side,
+// i.e. for certificates created with ResponsiblePartyForAcceptanceAct 2.
+//
+// Act is rendered to PDF from text/template with certificate and user profile data
+// and might be attached to document by nopaper.Client.AttachFile2Document and signed.
+package act
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/google/uuid"
+
+	nopaper "github.com/KaymeKaydex/go-nopaper-client"
+	"github.com/KaymeKaydex/go-nopaper-client/verify"
+)
+
+type Error string
+
+func (e Error) String() string {
+	return string(e)
+}
+
+func (e Error) Error() string {
+	return e.String()
+}
+
+var (
+	// ErrNoFont - font is required because standard PDF fonts have no cyrillic glyphs.
+	ErrNoFont Error = "utf-8 truetype font is required"
+)
+
+const (
+	defaultFontSize = 11
+	defaultFileName = "acceptance-act.pdf"
+	fontFamily      = "act"
+)
+
+// Data is a data of acceptance act available in template.
+type Data struct {
+	// Number is an optional number of act.
+	Number string
+	// Date is a date of act, default is today.
+	Date nopaper.Date
+	// Organization is an optional name of partner organization that issues certificate.
+	Organization string
+	Certificate  nopaper.CertificateInfo
+	Profile      nopaper.UserProfile
+	// Fingerprint is a SHA-256 fingerprint of certificate public key.
+	Fingerprint string
+}
+
+// NewData returns act data for certificate and its owner profile with public key fingerprint,
+// see verify.KeyFingerprint.
+func NewData(cert nopaper.CertificateInfo, profile nopaper.UserProfile) (Data, error) {
+	fingerprint, err := verify.KeyFingerprint(cert.CustomData.PublicKey)
+	if err != nil {
+		return Data{}, fmt.Errorf("certificate %s: %w", cert.ID, err)
+	}
+
+	return Data{
+		Date:        nopaper.DateOf(time.Now()),
+		Certificate: cert,
+		Profile:     profile,
+		Fingerprint: fingerprint,
+	}, nil
+}
+
+// Load fetches certificate and its owner profile from nopaper and returns act data.
+func Load(ctx context.Context, c *nopaper.Client, userGUID, certificateID uuid.UUID) (Data, error) {
+	certificates, err := c.UserSignaturesList(ctx, userGUID)
+	if err != nil {
+		return Data{}, fmt.Errorf("cant get certificates: %w", err)
+	}
+
+	cert, ok := certificates.ByID(certificateID)
+	if !ok {
+		return Data{}, fmt.Errorf("%w: %s", nopaper.ErrCertificateNotFound, certificateID)
+	}
+
+	profile, err := c.GetUserProfile(ctx, userGUID)
+	if err != nil {
+		return Data{}, fmt.Errorf("cant get user profile: %w", err)
+	}
+
+	return NewData(cert, *profile)
+}
+
+// Options - options of Generator.
+type Options struct {
+	// Template is a text/template of act, default is DefaultTemplate.
+	Template string
+	// Font is a content of utf-8 truetype font with cyrillic glyphs, e.g. DejaVuSans.ttf. Required.
+	Font []byte
+	// FontSize is a size of text in points, default is 11.
+	FontSize float64
+	// FileName is a name of attached file, default is acceptance-act.pdf.
+	FileName string
+}
+
+// Generator renders acceptance acts to PDF, it is safe for concurrent use.
+type Generator struct {
+	tmpl     *template.Template
+	font     []byte
+	fontSize float64
+	fileName string
+}
+
+// NewGenerator parses template and returns act generator.
+func NewGenerator(opts Options) (*Generator, error) {
+	if len(opts.Font) == 0 {
+		return nil, ErrNoFont
+	}
+
+	text := opts.Template
+	if text == "" {
+		text = DefaultTemplate
+	}
+
+	tmpl, err := template.New("act").Funcs(funcs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("cant parse act template: %w", err)
+	}
+
+	g := &Generator{
+		tmpl:     tmpl,
+		font:     opts.Font,
+		fontSize: opts.FontSize,
+		fileName: opts.FileName,
+	}
+
+	if g.fontSize <= 0 {
+		g.fontSize = defaultFontSize
+	}
+
+	if g.fileName == "" {
+		g.fileName = defaultFileName
+	}
+
+	return g, nil
+}
+
+// Render writes act PDF to w.
+func (g *Generator) Render(w io.Writer, data Data) error {
+	text := &bytes.Buffer{}
+
+	err := g.tmpl.Execute(text, data)
+	if err != nil {
+		return fmt.Errorf("cant execute act template: %w", err)
+	}
+
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(20, 20, 15)
+	pdf.SetAutoPageBreak(true, 20)
+	pdf.SetTitle("Acceptance act "+data.Certificate.ID.String(), true)
+	pdf.AddUTF8FontFromBytes(fontFamily, "", g.font)
+	pdf.SetFont(fontFamily, "", g.fontSize)
+	pdf.AddPage()
+
+	lineHeight := g.fontSize * 0.5
+
+	sc := bufio.NewScanner(text)
+	for sc.Scan() {
+		line := strings.TrimRight(sc.Text(), " \t\r")
+
+		switch {
+		case line == "":
+			pdf.Ln(lineHeight)
+		case strings.HasPrefix(line, "# "):
+			pdf.SetFontSize(g.fontSize * 1.2)
+			pdf.MultiCell(0, lineHeight*1.2, strings.TrimPrefix(line, "# "), "", "C", false)
+			pdf.SetFontSize(g.fontSize)
+		default:
+			pdf.MultiCell(0, lineHeight, line, "", "L", false)
+		}
+	}
+
+	if err := sc.Err(); err != nil {
+		return err
+	}
+
+	err = pdf.Output(w)
+	if err != nil {
+		return fmt.Errorf("cant render act pdf: %w", err)
+	}
+
+	return nil
+}
+
+// AttachRequest renders act and returns request for nopaper.Client.AttachFile2Document.
+func (g *Generator) AttachRequest(data Data) (nopaper.AttachFile2DocumentRequest, error) {
+	buf := &bytes.Buffer{}
+
+	err := g.Render(buf, data)
+	if err != nil {
+		return nopaper.AttachFile2DocumentRequest{}, err
+	}
+
+	return nopaper.AttachFile2DocumentRequest{
+		FileInfo: nopaper.FileInfo{
+			FileNameWithExtension: g.fileName,
+			Filebase64:            base64.StdEncoding.EncodeToString(buf.Bytes()),
+		},
+	}, nil
+}
+
+// Attach renders act and attaches it to document.
+func (g *Generator) Attach(ctx context.Context, c *nopaper.Client, documentID int, data Data) error {
+	req, err := g.AttachRequest(data)
+	if err != nil {
+		return err
+	}
+
+	return c.AttachFile2Document(ctx, documentID, req)
+}
+
+var funcs = template.FuncMap{
+	"date":      formatDate,
+	"fullName":  fullName,
+	"shortName": shortName,
+}
+
+// formatDate formats nopaper.Date or time.Time as DD.MM.YYYY, time is converted to UTC date.
+func formatDate(v any) (string, error) {
+	switch d := v.(type) {
+	case nopaper.Date:
+		if d.IsZero() {
+			return "", nil
+		}
+
+		return d.Time(time.UTC).Format("02.01.2006"), nil
+	case time.Time:
+		if d.IsZero() {
+			return "", nil
+		}
+
+		return d.UTC().Format("02.01.2006"), nil
+	default:
+		return "", fmt.Errorf("date: unsupported type %T", v)
+	}
+}
+
+// fullName returns surname, name and patronymic.
+func fullName(u nopaper.UserInfo) string {
+	return strings.Join(strings.Fields(u.Surname+" "+u.Name+" "+u.Patronymic), " ")
+}
+
+// shortName returns surname with initials, e.g. Иванов И. И.
+func shortName(u nopaper.UserInfo) string {
+	parts := []string{u.Surname}
+
+	for _, s := range []string{u.Name, u.Patronymic} {
+		if r := []rune(strings.TrimSpace(s)); len(r) > 0 {
+			parts = append(parts, string(r[0])+".")
+		}
+	}
+
+	return strings.Join(strings.Fields(strings.Join(parts, " ")), " ")
+}
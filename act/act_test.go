@@ -0,0 +1,248 @@
+package act
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	nopaper "github.com/KaymeKaydex/go-nopaper-client"
+	"github.com/KaymeKaydex/go-nopaper-client/verify"
+)
+
+// testdata/DejaVuSansCondensed.ttf is a copy of font bundled with github.com/go-pdf/fpdf.
+
+func readFont(t *testing.T) []byte {
+	t.Helper()
+
+	font, err := os.ReadFile("testdata/DejaVuSansCondensed.ttf")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return font
+}
+
+func testData(t *testing.T) Data {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	owner := uuid.New()
+
+	cert := nopaper.CertificateInfo{
+		ID:                    uuid.New(),
+		IssuedDateTimeUtc:     time.Date(2024, 3, 1, 22, 30, 0, 0, time.UTC),
+		ValidUntilDateTimeUtc: time.Date(2025, 3, 1, 22, 30, 0, 0, time.UTC),
+		OwnerName:             "Иванов Иван Иванович",
+		OwnerID:               owner,
+		CustomData:            nopaper.CertificateCustomData{PublicKey: base64.StdEncoding.EncodeToString(der)},
+	}
+
+	profile := nopaper.UserProfile{
+		UserGUID:  owner,
+		UserPhone: "79123456789",
+		Email:     "ivanov@example.com",
+		UserInfo: nopaper.UserInfo{
+			Surname:    "Иванов",
+			Name:       "Иван",
+			Patronymic: "Иванович",
+			PassportData: &nopaper.PassportData{
+				Series:               "4510",
+				Number:               "123456",
+				IssuedBy:             "ОВД Тверского района",
+				IssuingDate:          nopaper.DateOf(time.Date(2015, 6, 2, 0, 0, 0, 0, time.UTC)),
+				IssuerDepartmentCode: "770-001",
+			},
+		},
+	}
+
+	data, err := NewData(cert, profile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data.Number = "17"
+	data.Date = nopaper.DateOf(time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC))
+	data.Organization = "ООО «Ромашка»"
+
+	return data
+}
+
+func TestNewGeneratorNoFont(t *testing.T) {
+	if _, err := NewGenerator(Options{}); !errors.Is(err, ErrNoFont) {
+		t.Errorf("NewGenerator() error = %v, want %v", err, ErrNoFont)
+	}
+}
+
+func TestNewGeneratorInvalidTemplate(t *testing.T) {
+	if _, err := NewGenerator(Options{Font: readFont(t), Template: "{{.Number"}); err == nil {
+		t.Error("NewGenerator() error = nil")
+	}
+}
+
+func TestNewData(t *testing.T) {
+	data := testData(t)
+
+	want, err := verify.KeyFingerprint(data.Certificate.CustomData.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if data.Fingerprint != want {
+		t.Errorf("Fingerprint = %q, want %q", data.Fingerprint, want)
+	}
+
+	if _, err := NewData(nopaper.CertificateInfo{}, nopaper.UserProfile{}); err == nil {
+		t.Error("NewData() without public key error = nil")
+	}
+}
+
+func TestDefaultTemplate(t *testing.T) {
+	g, err := NewGenerator(Options{Font: readFont(t)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := testData(t)
+
+	var text strings.Builder
+	if err := g.tmpl.Execute(&text, data); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		"№ 17",
+		"Дата составления: 02.03.2024",
+		"ООО «Ромашка» передаёт, а владелец сертификата Иванов Иван Иванович принимает",
+		"Идентификатор сертификата: " + data.Certificate.ID.String(),
+		"Дата выдачи: 01.03.2024",
+		"Действителен до: 01.03.2025",
+		"Отпечаток ключа проверки (SHA-256): " + data.Fingerprint,
+		data.Certificate.CustomData.PublicKey,
+		"Телефон: 79123456789",
+		"Паспорт: 4510 123456, выдан ОВД Тверского района 02.06.2015, код подразделения 770-001",
+		"/ Иванов И. И. /",
+	} {
+		if !strings.Contains(text.String(), want) {
+			t.Errorf("act text has no %q:\n%s", want, text.String())
+		}
+	}
+
+	if strings.Contains(text.String(), "Дата рождения") {
+		t.Error("act text has empty birth date")
+	}
+}
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		pages    int
+	}{
+		{name: "default", pages: 1},
+		{name: "page break", template: strings.Repeat("{{.Certificate.OwnerName}}\n", 60), pages: 2},
+	}
+
+	for _, tt := range tests {
+		g, err := NewGenerator(Options{Font: readFont(t), Template: tt.template})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var buf bytes.Buffer
+		if err := g.Render(&buf, testData(t)); err != nil {
+			t.Fatalf("%s: Render() error = %v", tt.name, err)
+		}
+
+		pdf := buf.Bytes()
+		if !bytes.HasPrefix(pdf, []byte("%PDF-")) || !bytes.HasSuffix(bytes.TrimSpace(pdf), []byte("%%EOF")) {
+			t.Errorf("%s: Render() result is not pdf", tt.name)
+		}
+
+		if got := bytes.Count(pdf, []byte("/Type /Page\n")); got != tt.pages {
+			t.Errorf("%s: pages = %d, want %d", tt.name, got, tt.pages)
+		}
+
+		// Font must be embedded, standard PDF fonts have no cyrillic glyphs.
+		if !bytes.Contains(pdf, []byte("/FontFile2")) {
+			t.Errorf("%s: font is not embedded", tt.name)
+		}
+	}
+}
+
+func TestRenderErrors(t *testing.T) {
+	g, err := NewGenerator(Options{Font: readFont(t), Template: "{{.Unknown}}"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := g.Render(&bytes.Buffer{}, testData(t)); err == nil {
+		t.Error("Render() with unknown field error = nil")
+	}
+
+	g, err = NewGenerator(Options{Font: []byte("not a font")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := g.Render(&bytes.Buffer{}, testData(t)); err == nil {
+		t.Error("Render() with invalid font error = nil")
+	}
+}
+
+func TestAttachRequest(t *testing.T) {
+	g, err := NewGenerator(Options{Font: readFont(t), FileName: "act-17.pdf"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req, err := g.AttachRequest(testData(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if req.FileInfo.FileNameWithExtension != "act-17.pdf" {
+		t.Errorf("FileNameWithExtension = %q", req.FileInfo.FileNameWithExtension)
+	}
+
+	pdf, err := base64.StdEncoding.DecodeString(req.FileInfo.Filebase64)
+	if err != nil || !bytes.HasPrefix(pdf, []byte("%PDF-")) {
+		t.Errorf("Filebase64 is not base64 pdf: %v", err)
+	}
+}
+
+func TestShortName(t *testing.T) {
+	tests := []struct {
+		in   nopaper.UserInfo
+		want string
+	}{
+		{nopaper.UserInfo{Surname: "Иванов", Name: "Иван", Patronymic: "Иванович"}, "Иванов И. И."},
+		{nopaper.UserInfo{Surname: "Иванов", Name: "Иван"}, "Иванов И."},
+		{nopaper.UserInfo{Surname: "Иванов", Name: " "}, "Иванов"},
+		{nopaper.UserInfo{Name: "Иван"}, "И."},
+	}
+
+	for _, tt := range tests {
+		if got := shortName(tt.in); got != tt.want {
+			t.Errorf("shortName(%+v) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package act
+
+// DefaultTemplate is a template of acceptance act used when Options.Template is empty.
+//
+// Template renders text with simple markup: line started with "# " is a centered heading,
+// empty line is a paragraph gap, other lines are wrapped to page width.
+const DefaultTemplate = `# АКТ ПРИЁМА-ПЕРЕДАЧИ
+# сертификата ключа проверки электронной подписи
+{{if .Number}}№ {{.Number}}{{end}}
+Дата составления: {{date .Date}}
+
+{{with .Organization}}{{.}} передаёт, а {{end}}владелец сертификата {{fullName .Profile.UserInfo}} принимает сертификат ключа проверки электронной подписи со следующими данными:
+
+Идентификатор сертификата: {{.Certificate.ID}}
+Владелец сертификата: {{.Certificate.OwnerName}}
+Идентификатор владельца: {{.Certificate.OwnerID}}
+Дата выдачи: {{date .Certificate.IssuedDateTimeUtc}}
+Действителен до: {{date .Certificate.ValidUntilDateTimeUtc}}
+Отпечаток ключа проверки (SHA-256): {{.Fingerprint}}
+Ключ проверки электронной подписи:
+{{.Certificate.CustomData.PublicKey}}
+{{with .Profile}}
+Данные владельца:
+{{with .UserPhone}}Телефон: {{.}}
+{{end}}{{with .Email}}Email: {{.}}
+{{end}}{{with .BirthDate}}{{if not .IsZero}}Дата рождения: {{date .}}
+{{end}}{{end}}{{with .PassportData}}Паспорт: {{.Series}} {{.Number}}, выдан {{.IssuedBy}} {{date .IssuingDate}}{{with .IssuerDepartmentCode}}, код подразделения {{.}}{{end}}
+{{end}}{{end}}
+Владелец сертификата подтверждает, что ознакомлен с данными сертификата, они соответствуют его данным и ключ электронной подписи получен им лично.
+
+Владелец сертификата: ____________________ / {{shortName .Profile.UserInfo}} /
+`
@@ -0,0 +1,279 @@
+package nopaper
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CertificateStatus is a status of certificate(signature).
+type CertificateStatus int
+
+const (
+	// CertificateStatusTemplate - signature template.
+	CertificateStatusTemplate CertificateStatus = 1
+	// CertificateStatusInitialization - signature initialization.
+	CertificateStatusInitialization CertificateStatus = 2
+	// CertificateStatusInitializationError - signature initialization error.
+	CertificateStatusInitializationError CertificateStatus = 3
+	// CertificateStatusAvailable - signature is active.
+	CertificateStatusAvailable CertificateStatus = 4
+	// CertificateStatusBlocked - signature is blocked.
+	CertificateStatusBlocked CertificateStatus = 5
+	// CertificateStatusRevoked - signature is revoked.
+	CertificateStatusRevoked CertificateStatus = 6
+)
+
+var certificateStatusNames = map[CertificateStatus]string{
+	CertificateStatusTemplate:            "Template",
+	CertificateStatusInitialization:      "Initialization",
+	CertificateStatusInitializationError: "InitializationError",
+	CertificateStatusAvailable:           "Available",
+	CertificateStatusBlocked:             "Blocked",
+	CertificateStatusRevoked:             "Revoked",
+}
+
+func (s CertificateStatus) String() string {
+	return enumString(s, certificateStatusNames, "CertificateStatus")
+}
+
+// IsUsable reports whether certificate might be used for signing.
+func (s CertificateStatus) IsUsable() bool {
+	return s == CertificateStatusAvailable
+}
+
+// IsPending reports whether certificate is not ready yet and status will change.
+func (s CertificateStatus) IsPending() bool {
+	return s == CertificateStatusTemplate || s == CertificateStatusInitialization
+}
+
+// IsTerminal reports whether certificate status will never change
+// and certificate will never be usable. Blocked certificate might be unblocked, so it is not terminal.
+func (s CertificateStatus) IsTerminal() bool {
+	return s == CertificateStatusInitializationError || s == CertificateStatusRevoked
+}
+
+func (s CertificateStatus) MarshalJSON() ([]byte, error) {
+	return json.Marshal(int(s))
+}
+
+// UnmarshalJSON accepts both number and name of status.
+func (s *CertificateStatus) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, s, certificateStatusNames, "CertificateStatus")
+}
+
+// ProviderType is a provider of certificate.
+// Values are not documented by Nopaper, they might be named by RegisterProviderType.
+type ProviderType int
+
+var (
+	// enumMu guards names of enums registered at runtime.
+	enumMu sync.RWMutex
+
+	providerTypeNames = map[ProviderType]string{}
+	// issuingTypeSignatures is filled by RegisterIssuingType.
+	issuingTypeSignatures = map[IssuingType]SignatureType{}
+)
+
+// RegisterProviderType names provider type value, the name is returned by String and accepted by UnmarshalJSON.
+func RegisterProviderType(p ProviderType, name string) {
+	enumMu.Lock()
+	defer enumMu.Unlock()
+
+	providerTypeNames[p] = name
+}
+
+func (p ProviderType) String() string {
+	enumMu.RLock()
+	defer enumMu.RUnlock()
+
+	return enumString(p, providerTypeNames, "ProviderType")
+}
+
+func (p ProviderType) MarshalJSON() ([]byte, error) {
+	return json.Marshal(int(p))
+}
+
+func (p *ProviderType) UnmarshalJSON(data []byte) error {
+	enumMu.RLock()
+	defer enumMu.RUnlock()
+
+	return unmarshalEnum(data, p, providerTypeNames, "ProviderType")
+}
+
+// IssuingType is a way certificate is issued, it corresponds to SignatureType.
+// Values are not documented by Nopaper, so they must be bound to signature types by RegisterIssuingType
+// or Config.IssuingTypes, e.g. from captured UserSignaturesList responses.
+type IssuingType int
+
+// signatureTypeNames are names of issuing types bound to signature types.
+var signatureTypeNames = map[SignatureType]string{
+	SignatureTypeServer: "Server",
+	SignatureTypeSMS:    "SMS",
+}
+
+// RegisterIssuingType binds issuing type value to signature type for all clients,
+// it is used by CertificateInfo.SignatureType and IssuingType.String.
+// It returns error if value is already bound to another signature type.
+func RegisterIssuingType(i IssuingType, t SignatureType) error {
+	if _, ok := signatureTypeNames[t]; !ok {
+		return fmt.Errorf("unknown signature type %q for issuing type %d", t, i)
+	}
+
+	enumMu.Lock()
+	defer enumMu.Unlock()
+
+	if registered, ok := issuingTypeSignatures[i]; ok && registered != t {
+		return fmt.Errorf("issuing type %d is already registered as %s", i, registered)
+	}
+
+	issuingTypeSignatures[i] = t
+
+	return nil
+}
+
+// CheckIssuingType returns ErrIssuingTypeNotRegistered if no issuing type is bound to signature type,
+// since certificates of that type cant be told apart from others then.
+func CheckIssuingType(t SignatureType) error {
+	enumMu.RLock()
+	defer enumMu.RUnlock()
+
+	for _, registered := range issuingTypeSignatures {
+		if registered == t {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %s, see Config.IssuingTypes", ErrIssuingTypeNotRegistered, t)
+}
+
+// SignatureType returns signature type bound by RegisterIssuingType, it is empty for unknown types.
+func (i IssuingType) SignatureType() SignatureType {
+	enumMu.RLock()
+	defer enumMu.RUnlock()
+
+	return issuingTypeSignatures[i]
+}
+
+func (i IssuingType) String() string {
+	return enumString(i, issuingTypeNames(), "IssuingType")
+}
+
+func (i IssuingType) MarshalJSON() ([]byte, error) {
+	return json.Marshal(int(i))
+}
+
+// UnmarshalJSON accepts only number, since several issuing types might have the same name.
+func (i *IssuingType) UnmarshalJSON(data []byte) error {
+	return unmarshalEnum(data, i, nil, "IssuingType")
+}
+
+// issuingTypeNames returns names of registered issuing types.
+func issuingTypeNames() map[IssuingType]string {
+	enumMu.RLock()
+	defer enumMu.RUnlock()
+
+	names := make(map[IssuingType]string, len(issuingTypeSignatures))
+	for i, t := range issuingTypeSignatures {
+		names[i] = signatureTypeNames[t]
+	}
+
+	return names
+}
+
+// SignatureType returns signature type of certificate, it is empty if issuing type is not registered.
+func (ci CertificateInfo) SignatureType() SignatureType {
+	return ci.CustomData.IssuingType.SignatureType()
+}
+
+// IsUsable reports whether certificate is available and not expired at the moment.
+func (ci CertificateInfo) IsUsable(now time.Time) bool {
+	return ci.Status.IsUsable() && (ci.ValidUntilDateTimeUtc.IsZero() || ci.ValidUntilDateTimeUtc.After(now))
+}
+
+// CertificateList is a list of user certificates returned by UserSignaturesList.
+type CertificateList []CertificateInfo
+
+// ByID returns certificate by id.
+func (l CertificateList) ByID(id uuid.UUID) (CertificateInfo, bool) {
+	for _, ci := range l {
+		if ci.ID == id {
+			return ci, true
+		}
+	}
+
+	return CertificateInfo{}, false
+}
+
+// ActiveCertificate returns usable certificate of signature type that is valid for the longest time.
+func (l CertificateList) ActiveCertificate(t SignatureType) (CertificateInfo, bool) {
+	var (
+		best  CertificateInfo
+		found bool
+	)
+
+	now := time.Now()
+
+	for _, ci := range l {
+		if ci.SignatureType() != t || !ci.IsUsable(now) {
+			continue
+		}
+
+		if !found || ci.ValidUntilDateTimeUtc.After(best.ValidUntilDateTimeUtc) {
+			best, found = ci, true
+		}
+	}
+
+	return best, found
+}
+
+// ByStatus returns certificates with status.
+func (l CertificateList) ByStatus(s CertificateStatus) CertificateList {
+	var res CertificateList
+
+	for _, ci := range l {
+		if ci.Status == s {
+			res = append(res, ci)
+		}
+	}
+
+	return res
+}
+
+// enumString returns name of enum value or its number for unknown values.
+func enumString[T ~int](v T, names map[T]string, typeName string) string {
+	if name, ok := names[v]; ok {
+		return name
+	}
+
+	return typeName + "(" + strconv.Itoa(int(v)) + ")"
+}
+
+// unmarshalEnum decodes enum value from json number or name.
+func unmarshalEnum[T ~int](data []byte, v *T, names map[T]string, typeName string) error {
+	var n int
+	if err := json.Unmarshal(data, &n); err == nil {
+		*v = T(n)
+
+		return nil
+	}
+
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return fmt.Errorf("invalid %s %s: must be number or name", typeName, string(data))
+	}
+
+	for value, valueName := range names {
+		if valueName == name {
+			*v = value
+
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown %s %q", typeName, name)
+}
@@ -0,0 +1,292 @@
+package nopaper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Defaults of CertificateMonitorConfig.
+const (
+	defaultMonitorInterval      = time.Hour
+	defaultMonitorExpiryWarning = 14 * 24 * time.Hour
+	defaultMonitorRenewTimeout  = 10 * time.Minute
+)
+
+// CertificateEventType is a type of CertificateMonitor event.
+type CertificateEventType int
+
+const (
+	// CertificateExpiring - certificate expires within CertificateMonitorConfig.ExpiryWarning.
+	CertificateExpiring CertificateEventType = iota + 1
+	// CertificateExpired - certificate validity period is over.
+	CertificateExpired
+	// CertificateBlocked - certificate is blocked.
+	CertificateBlocked
+	// CertificateRevoked - certificate is revoked.
+	CertificateRevoked
+	// CertificateRenewed - new certificate is issued instead of expiring one.
+	CertificateRenewed
+	// CertificateRenewFailed - new certificate cant be issued, see CertificateEvent.Err.
+	CertificateRenewFailed
+)
+
+var certificateEventTypeNames = map[CertificateEventType]string{
+	CertificateExpiring:    "Expiring",
+	CertificateExpired:     "Expired",
+	CertificateBlocked:     "Blocked",
+	CertificateRevoked:     "Revoked",
+	CertificateRenewed:     "Renewed",
+	CertificateRenewFailed: "RenewFailed",
+}
+
+func (t CertificateEventType) String() string {
+	return enumString(t, certificateEventTypeNames, "CertificateEventType")
+}
+
+// CertificateEvent is an event about user certificate.
+type CertificateEvent struct {
+	Type        CertificateEventType
+	UserGUID    uuid.UUID
+	Certificate CertificateInfo
+	// Renewed is a new certificate for CertificateRenewed event.
+	Renewed *CertificateInfo
+	// Err is an error for CertificateRenewFailed event.
+	Err error
+}
+
+// CertificateNotifier receives monitor events, e.g. sends them to chat or email.
+type CertificateNotifier func(ctx context.Context, event CertificateEvent)
+
+// CertificateMonitorConfig - config of CertificateMonitor.
+type CertificateMonitorConfig struct {
+	// Users are users whose certificates are monitored, it might be changed by SetUsers.
+	Users []uuid.UUID
+	// Interval is a time between scans. Default is 1h.
+	Interval time.Duration
+	// ExpiryWarning is a period before expiration when CertificateExpiring is emitted. Default is 14 days.
+	ExpiryWarning time.Duration
+	// AutoRenew issues new certificate of the same signature type for expiring and expired certificates
+	// if user has no other certificate of this type valid beyond ExpiryWarning.
+	// Failed renewal is retried on the next scan, CertificateRenewFailed is emitted once per certificate state.
+	// Certificate of unregistered issuing type is not renewed and reported with ErrUnknownIssuingType.
+	AutoRenew bool
+	// RenewTimeout limits renewal of one certificate including wait for it, so stuck certificate
+	// does not block scan of other users. Default is 10m.
+	RenewTimeout time.Duration
+	// Notifiers receive every event.
+	Notifiers []CertificateNotifier
+}
+
+// CertificateMonitor periodically checks user certificates and emits events about expiration,
+// blocking and revocation. Every event is emitted once per certificate state,
+// it is emitted again if certificate returns to normal state and then gets the same problem.
+type CertificateMonitor struct {
+	c   *Client
+	cfg CertificateMonitorConfig
+
+	mu    sync.Mutex
+	users []uuid.UUID
+	// seen is a state of reported certificates by user and certificate id,
+	// certificates in normal state and users that are not monitored anymore are removed.
+	seen map[uuid.UUID]map[uuid.UUID]certificateState
+}
+
+// certificateState is a state of certificate reported by monitor.
+type certificateState struct {
+	// event is a last emitted event type.
+	event CertificateEventType
+	// renewed means that certificate is replaced by new one or replacement is not needed.
+	renewed bool
+	// renewFailed means that CertificateRenewFailed is emitted for the current event.
+	renewFailed bool
+}
+
+// NewCertificateMonitor creates monitor, it starts scanning on Run.
+func NewCertificateMonitor(c *Client, cfg CertificateMonitorConfig) *CertificateMonitor {
+	cfg.Interval = orDefault(cfg.Interval, defaultMonitorInterval)
+	cfg.ExpiryWarning = orDefault(cfg.ExpiryWarning, defaultMonitorExpiryWarning)
+	cfg.RenewTimeout = orDefault(cfg.RenewTimeout, defaultMonitorRenewTimeout)
+
+	return &CertificateMonitor{
+		c:     c,
+		cfg:   cfg,
+		users: slices.Clone(cfg.Users),
+		seen:  make(map[uuid.UUID]map[uuid.UUID]certificateState),
+	}
+}
+
+// SetUsers replaces monitored users, it is applied on the next scan.
+func (m *CertificateMonitor) SetUsers(users []uuid.UUID) {
+	m.mu.Lock()
+	m.users = slices.Clone(users)
+	m.mu.Unlock()
+}
+
+// Run scans certificates immediately and then every interval until context is done.
+// Scan errors are not fatal, they are reported to onError if it is not nil.
+func (m *CertificateMonitor) Run(ctx context.Context, onError func(error)) error {
+	ticker := time.NewTicker(m.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := m.Scan(ctx); err != nil && onError != nil && ctx.Err() == nil {
+			onError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Scan checks certificates of all users once, errors of users are joined.
+func (m *CertificateMonitor) Scan(ctx context.Context) error {
+	m.mu.Lock()
+	users := slices.Clone(m.users)
+	m.mu.Unlock()
+
+	var errs []error
+
+	for _, userGUID := range users {
+		if err := m.scanUser(ctx, userGUID); err != nil {
+			errs = append(errs, fmt.Errorf("user %s: %w", userGUID, err))
+		}
+
+		if ctx.Err() != nil {
+			errs = append(errs, ctx.Err())
+
+			break
+		}
+	}
+
+	m.mu.Lock()
+	for userGUID := range m.seen {
+		if !slices.Contains(users, userGUID) {
+			delete(m.seen, userGUID)
+		}
+	}
+	m.mu.Unlock()
+
+	return errors.Join(errs...)
+}
+
+// scanUser checks certificates of one user.
+func (m *CertificateMonitor) scanUser(ctx context.Context, userGUID uuid.UUID) error {
+	list, err := m.c.UserSignaturesList(ctx, userGUID)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	prev := m.seen[userGUID]
+	m.mu.Unlock()
+
+	now := time.Now()
+	next := make(map[uuid.UUID]certificateState)
+
+	for _, ci := range list {
+		eventType, ok := m.classify(ci, now)
+		if !ok {
+			// Certificate is back to normal state, so its next problem is reported again.
+			continue
+		}
+
+		state := prev[ci.ID]
+
+		if state.event != eventType {
+			state.event = eventType
+			state.renewFailed = false
+
+			m.notify(ctx, CertificateEvent{Type: eventType, UserGUID: userGUID, Certificate: ci})
+		}
+
+		if m.cfg.AutoRenew && !state.renewed && (eventType == CertificateExpiring || eventType == CertificateExpired) {
+			var err error
+
+			state.renewed, err = m.renew(ctx, userGUID, ci, list, now)
+			if err != nil && !state.renewFailed {
+				state.renewFailed = true
+
+				m.notify(ctx, CertificateEvent{Type: CertificateRenewFailed, UserGUID: userGUID, Certificate: ci, Err: err})
+			}
+		}
+
+		next[ci.ID] = state
+	}
+
+	m.mu.Lock()
+	m.seen[userGUID] = next
+	m.mu.Unlock()
+
+	return nil
+}
+
+// classify returns event type for certificate state, false means nothing to report.
+func (m *CertificateMonitor) classify(ci CertificateInfo, now time.Time) (CertificateEventType, bool) {
+	switch ci.Status {
+	case CertificateStatusBlocked:
+		return CertificateBlocked, true
+	case CertificateStatusRevoked:
+		return CertificateRevoked, true
+	case CertificateStatusAvailable:
+	default:
+		return 0, false
+	}
+
+	switch until := ci.ValidUntilDateTimeUtc; {
+	case until.IsZero():
+		return 0, false
+	case !until.After(now):
+		return CertificateExpired, true
+	case until.Before(now.Add(m.cfg.ExpiryWarning)):
+		return CertificateExpiring, true
+	default:
+		return 0, false
+	}
+}
+
+// renew issues new certificate if user has no other certificate of the same type
+// that is pending or valid beyond expiry warning. It returns false and error if renewal failed and must be retried.
+func (m *CertificateMonitor) renew(ctx context.Context, userGUID uuid.UUID, old CertificateInfo, list CertificateList, now time.Time) (bool, error) {
+	signatureType := old.SignatureType()
+	if signatureType == "" {
+		return false, fmt.Errorf("%w %s of certificate %s", ErrUnknownIssuingType, old.CustomData.IssuingType, old.ID)
+	}
+
+	for _, ci := range list {
+		// Certificate of unknown type might be a replacement, so it is not renewed twice.
+		if ci.ID == old.ID || (ci.SignatureType() != signatureType && ci.SignatureType() != "") {
+			continue
+		}
+
+		if ci.Status.IsPending() || ci.IsUsable(now.Add(m.cfg.ExpiryWarning)) {
+			return true, nil
+		}
+	}
+
+	renewCtx, cancel := context.WithTimeout(ctx, m.cfg.RenewTimeout)
+	defer cancel()
+
+	renewed, err := m.c.issueSignature(renewCtx, userGUID, signatureType, WaitOptions{})
+	if err != nil {
+		return false, err
+	}
+
+	m.notify(ctx, CertificateEvent{Type: CertificateRenewed, UserGUID: userGUID, Certificate: old, Renewed: &renewed})
+
+	return true, nil
+}
+
+func (m *CertificateMonitor) notify(ctx context.Context, event CertificateEvent) {
+	for _, n := range m.cfg.Notifiers {
+		n(ctx, event)
+	}
+}
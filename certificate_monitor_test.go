@@ -0,0 +1,103 @@
+package nopaper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// newCertificateServer serves certificates list and fails every certificate creation.
+func newCertificateServer(t *testing.T, list CertificateList, creates *atomic.Int32) *Client {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/certificate/list"):
+			_ = json.NewEncoder(w).Encode(UserSignaturesListResponse{CertificatePCServerInfoList: list})
+		case r.Method == http.MethodPost && strings.Contains(r.URL.Path, "/certificate/pay-control/"):
+			creates.Add(1)
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	c, err := NewClient(Config{URL: srv.URL, Token: "token"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return c
+}
+
+func TestCertificateMonitorRenewFailed(t *testing.T) {
+	const sms IssuingType = 9021
+
+	if err := RegisterIssuingType(sms, SignatureTypeSMS); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		issuingType IssuingType
+		creates     int32
+		err         error
+	}{
+		{name: "unknown issuing type", issuingType: 9022, err: ErrUnknownIssuingType},
+		{name: "create fails", issuingType: sms, creates: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userGUID := uuid.New()
+			expiring := CertificateInfo{
+				ID:                    uuid.New(),
+				Status:                CertificateStatusAvailable,
+				ValidUntilDateTimeUtc: time.Now().Add(24 * time.Hour),
+				CustomData:            CertificateCustomData{IssuingType: tt.issuingType},
+			}
+
+			var creates atomic.Int32
+
+			c := newCertificateServer(t, CertificateList{expiring}, &creates)
+
+			var events []CertificateEvent
+
+			m := NewCertificateMonitor(c, CertificateMonitorConfig{
+				Users:     []uuid.UUID{userGUID},
+				AutoRenew: true,
+				Notifiers: []CertificateNotifier{func(_ context.Context, e CertificateEvent) {
+					events = append(events, e)
+				}},
+			})
+
+			for range 2 {
+				if err := m.Scan(context.Background()); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if len(events) != 2 || events[0].Type != CertificateExpiring || events[1].Type != CertificateRenewFailed {
+				t.Fatalf("events = %v, want Expiring and one RenewFailed", events)
+			}
+
+			if tt.err != nil && !errors.Is(events[1].Err, tt.err) {
+				t.Errorf("RenewFailed error = %v, want %v", events[1].Err, tt.err)
+			}
+
+			// Failed renewal is retried on every scan but reported once.
+			if got := creates.Load(); got != tt.creates {
+				t.Errorf("creates = %d, want %d", got, tt.creates)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package nopaper
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestRegisterIssuingType(t *testing.T) {
+	const (
+		server IssuingType = 9001
+		sms    IssuingType = 9002
+	)
+
+	if err := RegisterIssuingType(server, SignatureTypeServer); err != nil {
+		t.Fatal(err)
+	}
+	if err := RegisterIssuingType(sms, SignatureTypeSMS); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := RegisterIssuingType(server, SignatureTypeServer); err != nil {
+		t.Errorf("RegisterIssuingType() same type again error = %v", err)
+	}
+	if err := RegisterIssuingType(server, SignatureTypeSMS); err == nil {
+		t.Error("RegisterIssuingType() another type error = nil")
+	}
+	if err := RegisterIssuingType(9003, "pc-unknown"); err == nil {
+		t.Error("RegisterIssuingType() unknown signature type error = nil")
+	}
+
+	tests := []struct {
+		issuingType IssuingType
+		name        string
+		signature   SignatureType
+	}{
+		{server, "Server", SignatureTypeServer},
+		{sms, "SMS", SignatureTypeSMS},
+		{9004, "IssuingType(9004)", ""},
+	}
+
+	for _, tt := range tests {
+		if got := tt.issuingType.String(); got != tt.name {
+			t.Errorf("IssuingType(%d).String() = %q, want %q", tt.issuingType, got, tt.name)
+		}
+
+		ci := CertificateInfo{CustomData: CertificateCustomData{IssuingType: tt.issuingType}}
+		if got := ci.SignatureType(); got != tt.signature {
+			t.Errorf("SignatureType() of issuing type %d = %q, want %q", tt.issuingType, got, tt.signature)
+		}
+	}
+
+	var decoded CertificateCustomData
+	if err := json.Unmarshal([]byte(`{"IssuingType":9002}`), &decoded); err != nil || decoded.IssuingType != sms {
+		t.Errorf("Unmarshal() = %d, %v, want %d", decoded.IssuingType, err, sms)
+	}
+
+	if err := json.Unmarshal([]byte(`{"IssuingType":"SMS"}`), &decoded); err == nil {
+		t.Error("Unmarshal() of name error = nil, several issuing types might have the same name")
+	}
+
+	if err := CheckIssuingType(SignatureTypeSMS); err != nil {
+		t.Errorf("CheckIssuingType() error = %v", err)
+	}
+}
+
+func TestCheckIssuingTypeNotRegistered(t *testing.T) {
+	err := CheckIssuingType("pc-unregistered")
+	if !errors.Is(err, ErrIssuingTypeNotRegistered) {
+		t.Errorf("CheckIssuingType() error = %v, want %v", err, ErrIssuingTypeNotRegistered)
+	}
+}
+
+func TestActiveCertificate(t *testing.T) {
+	const sms IssuingType = 9011
+
+	if err := RegisterIssuingType(sms, SignatureTypeSMS); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	cert := func(status CertificateStatus, issuingType IssuingType, validUntil time.Time) CertificateInfo {
+		return CertificateInfo{
+			ID:                    uuid.New(),
+			Status:                status,
+			ValidUntilDateTimeUtc: validUntil,
+			CustomData:            CertificateCustomData{IssuingType: issuingType},
+		}
+	}
+
+	longest := cert(CertificateStatusAvailable, sms, now.AddDate(1, 0, 0))
+	list := CertificateList{
+		cert(CertificateStatusAvailable, sms, now.AddDate(0, 1, 0)),
+		longest,
+		cert(CertificateStatusAvailable, sms, now.Add(-time.Hour)),
+		cert(CertificateStatusBlocked, sms, now.AddDate(2, 0, 0)),
+		cert(CertificateStatusAvailable, 9012, now.AddDate(3, 0, 0)),
+	}
+
+	got, ok := list.ActiveCertificate(SignatureTypeSMS)
+	if !ok || got.ID != longest.ID {
+		t.Errorf("ActiveCertificate() = %s, %v, want %s", got.ID, ok, longest.ID)
+	}
+
+	if _, ok := list.ActiveCertificate(SignatureTypeServer); ok {
+		t.Error("ActiveCertificate() of type without certificates found one")
+	}
+}
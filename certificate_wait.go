@@ -0,0 +1,101 @@
+package nopaper
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Defaults of WaitOptions.
+const (
+	defaultWaitInitialInterval = time.Second
+	defaultWaitMaxInterval     = 15 * time.Second
+	defaultWaitMultiplier      = 2
+)
+
+// WaitOptions - polling options of WaitForCertificate.
+type WaitOptions struct {
+	// InitialInterval is a wait before the second poll. Default is 1s.
+	InitialInterval time.Duration
+	// MaxInterval limits wait between polls. Default is 15s.
+	MaxInterval time.Duration
+	// Multiplier increases wait after every poll. Default is 2.
+	Multiplier float64
+	// Timeout limits the whole wait, zero means wait until context is done.
+	Timeout time.Duration
+}
+
+// CertificateStatusError is returned when certificate gets status that is not usable.
+// errors.Is matches it with ErrCertificateInitializationFailed, ErrCertificateBlocked
+// and ErrCertificateRevoked by status.
+type CertificateStatusError struct {
+	CertificateID uuid.UUID
+	Status        CertificateStatus
+}
+
+func (e *CertificateStatusError) Error() string {
+	return fmt.Sprintf("certificate %s has status %s", e.CertificateID, e.Status)
+}
+
+func (e *CertificateStatusError) Is(target error) bool {
+	switch target {
+	case ErrCertificateInitializationFailed:
+		return e.Status == CertificateStatusInitializationError
+	case ErrCertificateBlocked:
+		return e.Status == CertificateStatusBlocked
+	case ErrCertificateRevoked:
+		return e.Status == CertificateStatusRevoked
+	default:
+		return false
+	}
+}
+
+// WaitForCertificate polls user certificates with exponential backoff
+// until certificate becomes Available and returns it.
+// It returns *CertificateStatusError if certificate gets InitializationError, Blocked or Revoked status.
+func (c *Client) WaitForCertificate(ctx context.Context, userGUID, certificateID uuid.UUID, opts WaitOptions) (CertificateInfo, error) {
+	if certificateID == uuid.Nil {
+		return CertificateInfo{}, fmt.Errorf("certificate uuid cant be nil")
+	}
+
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	interval := orDefault(opts.InitialInterval, defaultWaitInitialInterval)
+	maxInterval := orDefault(opts.MaxInterval, defaultWaitMaxInterval)
+	multiplier := orDefault(opts.Multiplier, defaultWaitMultiplier)
+
+	for {
+		list, err := c.UserSignaturesList(ctx, userGUID)
+		if err != nil {
+			return CertificateInfo{}, fmt.Errorf("cant get certificates: %w", err)
+		}
+
+		// Certificate might be not listed right after creation, so it is polled until it appears.
+		if ci, ok := list.ByID(certificateID); ok {
+			switch ci.Status {
+			case CertificateStatusAvailable:
+				return ci, nil
+			case CertificateStatusInitializationError, CertificateStatusBlocked, CertificateStatusRevoked:
+				return ci, &CertificateStatusError{CertificateID: certificateID, Status: ci.Status}
+			}
+		}
+
+		t := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+
+			return CertificateInfo{}, fmt.Errorf("certificate %s is not available: %w", certificateID, ctx.Err())
+		case <-t.C:
+		}
+
+		interval = min(time.Duration(float64(interval)*multiplier), maxInterval)
+	}
+}
@@ -1,17 +1,33 @@
 package nopaper
 
 import (
-	"crypto/tls"
+	"context"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strings"
+	"time"
 )
 
+// apiPath is a path of partner api relative to Nopaper service url.
+const apiPath = "/partner-api/api/v2/external"
+
 // Client - nopaper service external client.
 type Client struct {
 	client *http.Client
 
 	url string
+	env Environment
+
+	smsCodeLength int
+	// smsMismatchCodes are Nopaper error codes of rejected sms code.
+	smsMismatchCodes map[string]struct{}
+
+	// rateLimit is nil if Config.RateLimit is not set.
+	rateLimit *rateLimitTransport
+
+	// phoneLocks serializes EnsureUser calls for the same phone.
+	phoneLocks keyedMutex
 }
 
 // Config for nopaper client.
@@ -19,32 +35,325 @@ type Config struct {
 	// URL is an url of Nopaper service.
 	// There is https://np-demo.abanking.ru/ for demo stand as example.
 	// It must exclude /partner-api/api/v2/external suffix.
+	// It might be empty if Environment is a preset.
 	URL string `yaml:"url"`
+	// Environment is a Nopaper stand: EnvDemo, EnvProduction or EnvCustom.
+	// It is detected by URL when empty, demo URL must match EnvDemo.
+	Environment Environment `yaml:"environment"`
+	// ForbidProductionInTests makes NewClient fail for every stand except demo and explicitly set EnvCustom,
+	// so tests never touch real documents even if production url is not recognized.
+	// It is set by test configs, e.g. ForbidProductionInTests: testing.Testing() in shared setup code;
+	// test binaries are not detected by the client, so production builds do not import testing.
+	ForbidProductionInTests bool `yaml:"forbid_production_in_tests"`
 	// Token is a Nopaper auth token, that contains in request header X-API-KEY.
 	Token string `yaml:"token"`
+	// TokenFile is a path to file with Nopaper auth token, e.g. mounted secret.
+	// It is used when Token is empty, surrounding whitespaces are trimmed.
+	// File is reread on change, so token is rotated without client rebuild.
+	TokenFile string `yaml:"token_file"`
+	// TokenSource provides token for every request, it is alternative to Token and TokenFile.
+	// Request rejected with 401 is retried once with a refreshed token.
+	TokenSource TokenSource `yaml:"-"`
 	// InsecureSkipVerify - ignores ssl certificates,
 	// it might be usefully if you have no CA certificates.
-	InsecureSkipVerify bool
+	InsecureSkipVerify bool `yaml:"insecure_skip_verify"`
+	// Timeout limits the whole request time including retries. Zero means no timeout.
+	Timeout time.Duration `yaml:"timeout"`
+	// RetryMax is a count of retries of idempotent requests
+	// on network errors and 429, 502, 503, 504 responses. Zero disables retries.
+	RetryMax int `yaml:"retry_max"`
+	// RetryWait is a wait before first retry, it doubles on every next one.
+	// Default is 500ms.
+	RetryWait time.Duration `yaml:"retry_wait"`
+	// ProxyURL is an url of http proxy for requests to Nopaper.
+	// HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables are used when it is empty.
+	ProxyURL string `yaml:"proxy_url"`
+
+	// CAFiles are paths to PEM files with additional trusted CA certificates,
+	// e.g. certificate of corporate MITM proxy. System pool is used as a base.
+	CAFiles []string `yaml:"ca_files"`
+	// CAPEM is PEM encoded additional trusted CA certificates.
+	CAPEM string `yaml:"ca_pem"`
+	// ClientCertFile and ClientKeyFile are paths to PEM encoded client certificate and key for mTLS.
+	ClientCertFile string `yaml:"client_cert_file"`
+	ClientKeyFile  string `yaml:"client_key_file"`
+	// ClientCertPEM and ClientKeyPEM are PEM encoded client certificate and key for mTLS,
+	// they are alternative to ClientCertFile and ClientKeyFile.
+	ClientCertPEM string `yaml:"client_cert_pem"`
+	ClientKeyPEM  string `yaml:"client_key_pem"`
+	// MinTLSVersion is a minimal TLS version: "1.2" (default) or "1.3".
+	MinTLSVersion string `yaml:"min_tls_version"`
+
+	// MaxIdleConns limits idle connections in pool. Default is 100.
+	MaxIdleConns int `yaml:"max_idle_conns"`
+	// MaxIdleConnsPerHost limits idle connections to Nopaper host. Default is 10.
+	MaxIdleConnsPerHost int `yaml:"max_idle_conns_per_host"`
+	// MaxConnsPerHost limits all connections to Nopaper host. Zero means no limit.
+	MaxConnsPerHost int `yaml:"max_conns_per_host"`
+	// IdleConnTimeout is a time after idle connection is closed. Default is 90s.
+	IdleConnTimeout time.Duration `yaml:"idle_conn_timeout"`
+	// DialTimeout limits connection establishing. Default is 30s.
+	DialTimeout time.Duration `yaml:"dial_timeout"`
+	// TLSHandshakeTimeout limits TLS handshake. Default is 10s.
+	TLSHandshakeTimeout time.Duration `yaml:"tls_handshake_timeout"`
+	// ResponseHeaderTimeout limits waiting of response headers after request is written.
+	// Zero means no limit.
+	ResponseHeaderTimeout time.Duration `yaml:"response_header_timeout"`
+
+	// RateLimit limits requests per second to Nopaper, retries are counted too.
+	// Zero means no limit.
+	RateLimit float64 `yaml:"rate_limit"`
+	// RateBurst is a count of requests that might be sent at once over RateLimit. Default is 1.
+	RateBurst int `yaml:"rate_burst"`
+	// Metrics observes every request to Nopaper.
+	Metrics MetricsObserver `yaml:"-"`
+	// MetricsLabels are passed to Metrics with every request, e.g. service or tenant name.
+	MetricsLabels map[string]string `yaml:"metrics_labels"`
+
+	// SMSCodeLength is an exact count of digits in sms code, see NormalizeSMSCode.
+	// Zero means any count from 4 to 8.
+	SMSCodeLength int `yaml:"sms_code_length"`
+	// SMSCodeMismatchErrorCodes are Nopaper error codes of 400 response of ConfirmSMSSign
+	// that mean wrong sms code, they are returned as *SMSCodeError and counted by SMSSigningSession.
+	// Other codes are returned as usual errors and dont spend session attempts.
+	// Nopaper does not document mismatch codes, so SMSSigningSession requires them.
+	SMSCodeMismatchErrorCodes []string `yaml:"sms_code_mismatch_error_codes"`
+
+	// IssuingTypes maps CertificateCustomData.IssuingType values to signature types,
+	// e.g. from captured UserSignaturesList responses, since Nopaper does not document them.
+	// They are registered by NewClient with RegisterIssuingType for all clients.
+	// EnsureSignature, ResolveSignatureID, SignAs and certificate renewal require signature type to be mapped
+	// and return ErrIssuingTypeNotRegistered otherwise.
+	IssuingTypes map[IssuingType]SignatureType `yaml:"issuing_types"`
+}
+
+// Validate checks config and returns descriptive error for the first invalid field.
+func (cfg Config) Validate() error {
+	return cfg.validate(true)
+}
+
+// validate checks config, token might be not required for shared config of ClientPool.
+func (cfg Config) validate(requireToken bool) error {
+	if err := cfg.Environment.validate(cfg.URL); err != nil {
+		return err
+	}
+
+	if cfg.URL == "" {
+		if cfg.Environment.IsPreset() {
+			cfg.URL = cfg.Environment.BaseURL()
+		} else {
+			return fmt.Errorf("url is required")
+		}
+	}
+
+	u, err := url.Parse(cfg.URL)
+	if err != nil {
+		return fmt.Errorf("invalid url %q: %w", cfg.URL, err)
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("invalid url %q: scheme must be http or https", cfg.URL)
+	}
+
+	if u.Host == "" {
+		return fmt.Errorf("invalid url %q: host is empty", cfg.URL)
+	}
+
+	if strings.Contains(u.Path, "/partner-api") {
+		return fmt.Errorf("invalid url %q: it must exclude %s suffix", cfg.URL, apiPath)
+	}
+
+	switch tokens := btoi(cfg.Token != "") + btoi(cfg.TokenFile != "") + btoi(cfg.TokenSource != nil); {
+	case tokens == 0 && requireToken:
+		return fmt.Errorf("token, token_file or token source is required")
+	case tokens > 1:
+		return fmt.Errorf("token, token_file and token source are mutually exclusive")
+	}
+
+	if cfg.Timeout < 0 {
+		return fmt.Errorf("timeout cant be negative")
+	}
+
+	if cfg.RetryMax < 0 {
+		return fmt.Errorf("retry_max cant be negative")
+	}
+
+	if cfg.RetryWait < 0 {
+		return fmt.Errorf("retry_wait cant be negative")
+	}
+
+	if cfg.ProxyURL != "" {
+		if _, err := url.Parse(cfg.ProxyURL); err != nil {
+			return fmt.Errorf("invalid proxy_url %q: %w", cfg.ProxyURL, err)
+		}
+	}
+
+	if _, err := parseTLSVersion(cfg.MinTLSVersion); err != nil {
+		return err
+	}
+
+	if (cfg.ClientCertFile == "") != (cfg.ClientKeyFile == "") {
+		return fmt.Errorf("client_cert_file and client_key_file must be set together")
+	}
+
+	if (cfg.ClientCertPEM == "") != (cfg.ClientKeyPEM == "") {
+		return fmt.Errorf("client_cert_pem and client_key_pem must be set together")
+	}
+
+	if cfg.ClientCertFile != "" && cfg.ClientCertPEM != "" {
+		return fmt.Errorf("client certificate must be set either by files or by pem")
+	}
+
+	for _, d := range []struct {
+		name string
+		v    time.Duration
+	}{
+		{"idle_conn_timeout", cfg.IdleConnTimeout},
+		{"dial_timeout", cfg.DialTimeout},
+		{"tls_handshake_timeout", cfg.TLSHandshakeTimeout},
+		{"response_header_timeout", cfg.ResponseHeaderTimeout},
+	} {
+		if d.v < 0 {
+			return fmt.Errorf("%s cant be negative", d.name)
+		}
+	}
+
+	if cfg.MaxIdleConns < 0 || cfg.MaxIdleConnsPerHost < 0 || cfg.MaxConnsPerHost < 0 {
+		return fmt.Errorf("connection pool limits cant be negative")
+	}
+
+	if cfg.RateLimit < 0 || cfg.RateBurst < 0 {
+		return fmt.Errorf("rate_limit and rate_burst cant be negative")
+	}
+
+	if cfg.SMSCodeLength < 0 {
+		return fmt.Errorf("sms_code_length cant be negative")
+	}
+
+	for issuingType, signatureType := range cfg.IssuingTypes {
+		if signatureType != SignatureTypeServer && signatureType != SignatureTypeSMS {
+			return fmt.Errorf("issuing_types: unknown signature type %q for issuing type %d", signatureType, issuingType)
+		}
+	}
+
+	return nil
 }
 
 // NewClient creates new Nopaper service client.
 func NewClient(cfg Config) (*Client, error) {
-	if cfg.Token == "" {
-		return nil, fmt.Errorf("token cant be nil")
-	}
 	// Preparing URL for a work.
 	cfg.URL = strings.ReplaceAll(cfg.URL, " ", "")
 	cfg.URL = strings.TrimSuffix(cfg.URL, "/")
 
-	tr := &http.Transport{
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: cfg.InsecureSkipVerify},
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	tr, err := newHTTPTransport(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return newClient(cfg, tr)
+}
+
+// newClient creates client over base transport, config must be already validated.
+// Base transport might be shared between clients, e.g. by ClientPool.
+func newClient(cfg Config, base http.RoundTripper) (*Client, error) {
+	env := cfg.Environment
+	if env == "" {
+		env = EnvironmentByURL(cfg.URL)
+	}
+
+	if cfg.URL == "" {
+		cfg.URL = env.BaseURL()
+	}
+
+	for issuingType, signatureType := range cfg.IssuingTypes {
+		if err := RegisterIssuingType(issuingType, signatureType); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.ForbidProductionInTests && env != EnvDemo && cfg.Environment != EnvCustom {
+		return nil, ErrProductionInTest
+	}
+
+	tokens := cfg.TokenSource
+	switch {
+	case cfg.Token != "":
+		tokens = StaticTokenSource(cfg.Token)
+	case cfg.TokenFile != "":
+		fileTokens := NewFileTokenSource(cfg.TokenFile)
+		// Fail fast on missing secret.
+		if _, err := fileTokens.Token(context.Background()); err != nil {
+			return nil, err
+		}
+
+		tokens = fileTokens
+	}
+
+	client := &http.Client{Transport: base, Timeout: cfg.Timeout}
+
+	client.Transport = newAuthHeaderTransport(base, tokens)
+
+	var rateLimit *rateLimitTransport
+	if cfg.RateLimit > 0 {
+		rateLimit = newRateLimitTransport(client.Transport, cfg.RateLimit, cfg.RateBurst)
+		client.Transport = rateLimit
 	}
-	client := &http.Client{Transport: tr}
 
-	client.Transport = newAuthHeaderTransport(tr, cfg.Token)
+	if cfg.RetryMax > 0 {
+		client.Transport = newRetryTransport(client.Transport, cfg.RetryMax, cfg.RetryWait)
+	}
+
+	if cfg.Metrics != nil {
+		client.Transport = newMetricsTransport(client.Transport, cfg.Metrics, cfg.MetricsLabels)
+	}
 
 	return &Client{
 		client: client,
-		url:    cfg.URL + "/partner-api/api/v2/external",
+		url:    cfg.URL + apiPath,
+		env:    env,
+
+		rateLimit: rateLimit,
+
+		smsCodeLength:    cfg.SMSCodeLength,
+		smsMismatchCodes: smsMismatchCodes(cfg.SMSCodeMismatchErrorCodes),
 	}, nil
 }
+
+// waitRate waits for rate limiter in advance, e.g. while waiting might be canceled
+// but the request itself must not be. Request context must be wrapped by withReservedRate then.
+func (c *Client) waitRate(ctx context.Context) error {
+	if c.rateLimit == nil {
+		return nil
+	}
+
+	return c.rateLimit.limiter.Wait(ctx)
+}
+
+// smsMismatchCodes returns set of configured codes.
+func smsMismatchCodes(configured []string) map[string]struct{} {
+	codes := make(map[string]struct{}, len(configured))
+
+	for _, code := range configured {
+		codes[code] = struct{}{}
+	}
+
+	return codes
+}
+
+// Environment returns Nopaper stand that client works with.
+func (c *Client) Environment() Environment {
+	return c.env
+}
+
+// btoi converts bool to int.
+func btoi(b bool) int {
+	if b {
+		return 1
+	}
+
+	return 0
+}
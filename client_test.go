@@ -0,0 +1,34 @@
+package nopaper
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestForbidProductionInTests(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		err  error
+	}{
+		{name: "demo", cfg: Config{Environment: EnvDemo}},
+		{name: "demo by url", cfg: Config{URL: "https://np-demo.abanking.ru"}},
+		{name: "explicit custom", cfg: Config{Environment: EnvCustom, URL: "https://np.local"}},
+		{name: "production", cfg: Config{Environment: EnvProduction, URL: "https://np.example"}, err: ErrProductionInTest},
+		{name: "unknown url", cfg: Config{URL: "https://np.example"}, err: ErrProductionInTest},
+	}
+
+	for _, tt := range tests {
+		tt.cfg.Token = "token"
+
+		if _, err := NewClient(tt.cfg); err != nil {
+			t.Errorf("%s: NewClient() without guard error = %v", tt.name, err)
+		}
+
+		tt.cfg.ForbidProductionInTests = true
+
+		if _, err := NewClient(tt.cfg); !errors.Is(err, tt.err) {
+			t.Errorf("%s: NewClient() error = %v, want %v", tt.name, err, tt.err)
+		}
+	}
+}
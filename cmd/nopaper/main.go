@@ -0,0 +1,189 @@
+// Command nopaper is a small operator tool on top of the nopaper client.
+//
+// Usage:
+//
+//	nopaper <command> [flags]
+//
+// Commands:
+//
+//	sms-sign   interactive SMS signing of a document
+//	onboard    bulk contractors onboarding from csv
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	nopaper "github.com/KaymeKaydex/go-nopaper-client"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	var err error
+
+	switch cmd, args := os.Args[1], os.Args[2:]; cmd {
+	case "sms-sign":
+		err = runSMSSign(args)
+	case "onboard":
+		err = runOnboard(args)
+	case "help", "-h", "--help":
+		usage()
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", cmd)
+		usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprint(os.Stderr, `Usage: nopaper <command> [flags]
+
+Commands:
+  sms-sign   interactive SMS signing of a document
+  onboard    bulk contractors onboarding from csv
+
+Run "nopaper <command> -h" for command flags.
+`)
+}
+
+// clientFlags are common flags for every command that talks to Nopaper.
+type clientFlags struct {
+	fs       *flag.FlagSet
+	config   string
+	url      string
+	token    string
+	insecure bool
+	// smsMismatchCodes are comma separated, flag is registered only by commands that confirm sms codes.
+	smsMismatchCodes string
+}
+
+// register adds client flags to a command flag set.
+func (cf *clientFlags) register(fs *flag.FlagSet) {
+	cf.fs = fs
+
+	fs.StringVar(&cf.config, "config", os.Getenv("NOPAPER_CONFIG"), "yaml config file (env NOPAPER_CONFIG)")
+	fs.StringVar(&cf.url, "url", "", "Nopaper service url, overrides config (env NOPAPER_URL if not in config)")
+	fs.StringVar(&cf.token, "token", "", "Nopaper partner token, overrides config (env NOPAPER_TOKEN if not in config)")
+	fs.BoolVar(&cf.insecure, "insecure", false, "skip TLS certificate verification")
+}
+
+// registerSMS adds flags of sms code confirmation to a command flag set.
+func (cf *clientFlags) registerSMS(fs *flag.FlagSet) {
+	fs.StringVar(&cf.smsMismatchCodes, "sms-mismatch-codes", "",
+		"comma separated Nopaper error codes of wrong sms code, overrides config (env NOPAPER_SMS_CODE_MISMATCH_ERROR_CODES if not in config)")
+}
+
+// client creates nopaper client from parsed flags.
+// Flags set explicitly override values from config file,
+// NOPAPER_URL, NOPAPER_TOKEN, NOPAPER_SMS_CODE_MISMATCH_ERROR_CODES and NOPAPER_ISSUING_TYPES
+// environment variables are used only for values missing in both.
+func (cf *clientFlags) client() (*nopaper.Client, error) {
+	cfg := nopaper.Config{}
+
+	if cf.config != "" {
+		var err error
+
+		cfg, err = nopaper.ReadConfigFile(cf.config)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	cf.fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "url":
+			cfg.URL = cf.url
+		case "token":
+			cfg.Token = cf.token
+			cfg.TokenFile = ""
+		case "insecure":
+			cfg.InsecureSkipVerify = cf.insecure
+		case "sms-mismatch-codes":
+			cfg.SMSCodeMismatchErrorCodes = splitList(cf.smsMismatchCodes)
+		}
+	})
+
+	if cfg.URL == "" && cfg.Environment == "" {
+		cfg.URL = os.Getenv("NOPAPER_URL")
+	}
+
+	if cfg.Token == "" && cfg.TokenFile == "" && cfg.TokenSource == nil {
+		cfg.Token = os.Getenv("NOPAPER_TOKEN")
+	}
+
+	if len(cfg.SMSCodeMismatchErrorCodes) == 0 {
+		cfg.SMSCodeMismatchErrorCodes = splitList(os.Getenv("NOPAPER_SMS_CODE_MISMATCH_ERROR_CODES"))
+	}
+
+	if len(cfg.IssuingTypes) == 0 {
+		var err error
+
+		cfg.IssuingTypes, err = issuingTypes(os.Getenv("NOPAPER_ISSUING_TYPES"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid NOPAPER_ISSUING_TYPES: %w", err)
+		}
+	}
+
+	err := cfg.Validate()
+	if err != nil {
+		if cf.config != "" {
+			return nil, fmt.Errorf("invalid config %s with flags: %w", cf.config, err)
+		}
+
+		return nil, fmt.Errorf("invalid flags: %w", err)
+	}
+
+	return nopaper.NewClient(cfg)
+}
+
+// splitList splits comma separated values and drops empty ones.
+func splitList(s string) []string {
+	var res []string
+
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			res = append(res, v)
+		}
+	}
+
+	return res
+}
+
+// issuingTypes parses comma separated pairs like 1=pc-server,2=pc-sms.
+func issuingTypes(s string) (map[nopaper.IssuingType]nopaper.SignatureType, error) {
+	items := splitList(s)
+	if len(items) == 0 {
+		return nil, nil
+	}
+
+	res := make(map[nopaper.IssuingType]nopaper.SignatureType, len(items))
+
+	for _, item := range items {
+		k, v, ok := strings.Cut(item, "=")
+		if !ok {
+			return nil, fmt.Errorf("%q must be issuing_type=signature_type", item)
+		}
+
+		i, err := strconv.Atoi(strings.TrimSpace(k))
+		if err != nil {
+			return nil, fmt.Errorf("invalid issuing type %q: %w", k, err)
+		}
+
+		res[nopaper.IssuingType(i)] = nopaper.SignatureType(strings.TrimSpace(v))
+	}
+
+	return res, nil
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"time"
+
+	nopaper "github.com/KaymeKaydex/go-nopaper-client"
+	"github.com/KaymeKaydex/go-nopaper-client/onboarding"
+)
+
+// runOnboard onboards contractors from csv and writes per row results to csv.
+func runOnboard(args []string) error {
+	flags := flag.NewFlagSet("onboard", flag.ExitOnError)
+
+	var (
+		cf            clientFlags
+		in            string
+		out           string
+		concurrency   int
+		signatureType string
+		resume        bool
+		waitTimeout   time.Duration
+	)
+
+	cf.register(flags)
+	flags.StringVar(&in, "in", "", "input csv with contractors")
+	flags.StringVar(&out, "out", "onboarding-results.csv", "output csv with results")
+	flags.IntVar(&concurrency, "concurrency", 4, "rows processed in parallel")
+	flags.StringVar(&signatureType, "signature-type", nopaper.SignatureTypeSMS.String(), "certificate type: pc-sms or pc-server")
+	flags.DurationVar(&waitTimeout, "wait-timeout", nopaper.EnsureSignatureWaitTimeout, "max wait for issued certificate of one row")
+	flags.BoolVar(&resume, "resume", false, "skip rows that are successful in existing output and append to it")
+
+	_ = flags.Parse(args)
+
+	if in == "" {
+		return fmt.Errorf("-in is required")
+	}
+
+	c, err := cf.client()
+	if err != nil {
+		return err
+	}
+
+	inFile, err := os.Open(in)
+	if err != nil {
+		return err
+	}
+	defer inFile.Close()
+
+	rows, err := onboarding.ReadRows(inFile)
+	if err != nil {
+		return err
+	}
+
+	opts := onboarding.Options{
+		Concurrency:   concurrency,
+		SignatureType: nopaper.SignatureType(signatureType),
+		Wait:          nopaper.WaitOptions{Timeout: waitTimeout},
+	}
+
+	outFlags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	withHeader := true
+
+	if resume {
+		prev, err := os.Open(out)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+		case err != nil:
+			return err
+		default:
+			results, err := onboarding.ReadResults(prev)
+			prev.Close()
+
+			if err != nil {
+				return err
+			}
+
+			opts.Skip = onboarding.Completed(results)
+			outFlags = os.O_WRONLY | os.O_APPEND
+			withHeader = len(results) == 0
+		}
+	}
+
+	outFile, err := os.OpenFile(out, outFlags, 0o644)
+	if err != nil {
+		return err
+	}
+	defer outFile.Close()
+
+	w, err := onboarding.NewResultWriter(outFile, withHeader)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	var ok, failed atomic.Int64
+
+	err = onboarding.Run(ctx, c, rows, opts, func(r onboarding.Result) {
+		if r.OK() {
+			ok.Add(1)
+		} else {
+			failed.Add(1)
+			fmt.Fprintf(os.Stderr, "line %d: %s: %s\n", r.Line, r.ErrorCode, r.Error)
+		}
+
+		if err := w.Write(r); err != nil {
+			fmt.Fprintf(os.Stderr, "cant write result of line %d: %v\n", r.Line, err)
+		}
+	})
+	if errors.Is(err, nopaper.ErrIssuingTypeNotRegistered) {
+		return fmt.Errorf("%w (set issuing_types in -config or NOPAPER_ISSUING_TYPES)", err)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Onboarded %d, failed %d, skipped %d. Results are in %s\n", ok.Load(), failed.Load(), len(opts.Skip), out)
+
+	if failed.Load() > 0 {
+		return fmt.Errorf("%d rows failed", failed.Load())
+	}
+
+	return nil
+}
@@ -0,0 +1,276 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	nopaper "github.com/KaymeKaydex/go-nopaper-client"
+)
+
+// runSMSSign walks an operator through SMS signing of a document:
+// it sends the code, asks for it on stdin, confirms it and shows stamped files.
+func runSMSSign(args []string) error {
+	fs := flag.NewFlagSet("sms-sign", flag.ExitOnError)
+
+	var (
+		cf          clientFlags
+		documentID  int
+		signatureID string
+		maxAttempts int
+		outDir      string
+	)
+
+	cf.register(fs)
+	cf.registerSMS(fs)
+	fs.IntVar(&documentID, "document", 0, "document id to sign")
+	fs.StringVar(&signatureID, "signature", "", "signature(certificate) id of the signer")
+	fs.IntVar(&maxAttempts, "attempts", nopaper.DefaultSMSMaxAttempts, "wrong code attempts before a new code is sent")
+	fs.StringVar(&outDir, "out", "", "directory to download stamped files to")
+
+	_ = fs.Parse(args)
+
+	if documentID == 0 {
+		return fmt.Errorf("-document is required")
+	}
+
+	sigID, err := uuid.Parse(signatureID)
+	if err != nil {
+		return fmt.Errorf("invalid -signature: %w", err)
+	}
+
+	c, err := cf.client()
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	s := &smsSigner{
+		c:           c,
+		in:          bufio.NewReader(os.Stdin),
+		out:         os.Stdout,
+		documentID:  documentID,
+		signatureID: sigID,
+		maxAttempts: maxAttempts,
+	}
+
+	if err := s.sign(ctx); err != nil {
+		return err
+	}
+
+	return showStampedFiles(ctx, c, os.Stdout, documentID, outDir)
+}
+
+// errAborted is returned when operator quits signing.
+var errAborted = errors.New("signing aborted by operator")
+
+// smsSigner keeps state of one interactive SMS signing.
+type smsSigner struct {
+	c   *nopaper.Client
+	in  *bufio.Reader
+	out io.Writer
+
+	documentID  int
+	signatureID uuid.UUID
+	maxAttempts int
+}
+
+func (s *smsSigner) sign(ctx context.Context) error {
+	session := nopaper.NewSMSSigningSession(s.documentID, s.signatureID)
+	if s.maxAttempts > 0 {
+		session.MaxAttempts = s.maxAttempts
+	}
+
+	err := session.Start(ctx, s.c)
+	if errors.Is(err, nopaper.ErrSMSMismatchCodesNotConfigured) {
+		return fmt.Errorf("%w: set -sms-mismatch-codes or sms_code_mismatch_error_codes in config", err)
+	}
+
+	if err != nil {
+		return fmt.Errorf("cant send sms code: %w", err)
+	}
+
+	fmt.Fprintf(s.out, "SMS code sent for document %d.\n", s.documentID)
+
+	for {
+		fmt.Fprint(s.out, "Enter SMS code ([r]esend, [q]uit): ")
+
+		line, err := s.in.ReadString('\n')
+		if err != nil && line == "" {
+			if err == io.EOF {
+				return errAborted
+			}
+
+			return err
+		}
+
+		switch input := strings.TrimSpace(line); strings.ToLower(input) {
+		case "":
+			continue
+		case "q", "quit":
+			return errAborted
+		case "r", "resend":
+			if err := s.resend(ctx, session); err != nil {
+				return err
+			}
+		default:
+			outcome, err := session.Confirm(ctx, s.c, input)
+			if err != nil && outcome != nopaper.SMSConfirmInvalidCode {
+				if ctx.Err() != nil {
+					return fmt.Errorf("cant confirm sms code: %w", err)
+				}
+
+				// Nopaper might reject code with error code that is not configured as mismatch,
+				// operator decides whether to retry, resend or quit.
+				fmt.Fprintf(s.out, "Code is not accepted: %v\n", err)
+
+				continue
+			}
+
+			switch outcome {
+			case nopaper.SMSConfirmSigned:
+				fmt.Fprintln(s.out, "Document is signed.")
+
+				return nil
+			case nopaper.SMSConfirmInvalidCode:
+				fmt.Fprintf(s.out, "Code is not recognized (%s), enter digits from SMS.\n", smsCodeReason(err))
+			case nopaper.SMSConfirmWrongCode:
+				fmt.Fprintf(s.out, "Wrong code, %d attempts left.\n", session.AttemptsLeft())
+			case nopaper.SMSConfirmCodeExpired:
+				fmt.Fprintln(s.out, "Code is expired or attempts are over, sending a new code.")
+
+				if err := s.resend(ctx, session); err != nil {
+					return err
+				}
+			case nopaper.SMSConfirmLockedOut:
+				return nopaper.ErrSMSLockedOut
+			}
+		}
+	}
+}
+
+// smsCodeReason describes why code is not accepted for operator.
+func smsCodeReason(err error) string {
+	var codeErr *nopaper.SMSCodeError
+	if !errors.As(err, &codeErr) {
+		return "unknown reason"
+	}
+
+	switch codeErr.Reason {
+	case nopaper.SMSCodeEmpty, nopaper.SMSCodeNoDigits:
+		return "no digits"
+	case nopaper.SMSCodeWrongLength:
+		return "wrong count of digits"
+	case nopaper.SMSCodeAmbiguous:
+		return "several codes in text"
+	default:
+		return string(codeErr.Reason)
+	}
+}
+
+// resend asks Nopaper to send new SMS code, cooldown is reported to operator and is not an error.
+func (s *smsSigner) resend(ctx context.Context, session *nopaper.SMSSigningSession) error {
+	err := session.Resend(ctx, s.c)
+
+	var tooEarly *nopaper.ResendTooEarlyError
+	if errors.As(err, &tooEarly) {
+		fmt.Fprintf(s.out, "New code might be sent in %s, enter [r] later.\n", tooEarly.RetryAfter.Round(time.Second))
+
+		return nil
+	}
+
+	if errors.Is(err, nopaper.ErrSMSLockedOut) {
+		fmt.Fprintln(s.out, "No more codes might be sent, enter the last one.")
+
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("cant resend sms code: %w", err)
+	}
+
+	fmt.Fprintf(s.out, "SMS code sent for document %d.\n", s.documentID)
+
+	return nil
+}
+
+// showStampedFiles prints stamped files of document and downloads them to outDir if it is set.
+func showStampedFiles(ctx context.Context, c *nopaper.Client, w io.Writer, documentID int, outDir string) error {
+	files, err := c.GetFileIDsInDocument(ctx, documentID)
+	if err != nil {
+		return fmt.Errorf("cant get document files: %w", err)
+	}
+
+	groups := []struct {
+		name  string
+		files []nopaper.FileIDInfo
+	}{
+		{"Files with stamp", files.OriginFileWithStampList},
+		{"Oferta with stamp", files.OfertaWithStampList},
+		{"Procuratory with stamp", files.ProcuratoryWithStampList},
+	}
+
+	var toDownload []nopaper.GetFilesByIDRequest
+
+	for _, g := range groups {
+		if len(g.files) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(w, "%s:\n", g.name)
+
+		for _, f := range g.files {
+			fmt.Fprintf(w, "  %s  %s (%d KB)\n", f.FileID, f.OriginNameWithExtension, f.SizeKb)
+
+			toDownload = append(toDownload, nopaper.GetFilesByIDRequest{FileID: f.FileID, DocumentID: documentID})
+		}
+	}
+
+	if len(toDownload) == 0 {
+		fmt.Fprintln(w, "No stamped files yet.")
+
+		return nil
+	}
+
+	if outDir == "" {
+		return nil
+	}
+
+	downloaded, err := c.GetFilesByID(ctx, toDownload)
+	if err != nil {
+		return fmt.Errorf("cant download stamped files: %w", err)
+	}
+
+	if err := os.MkdirAll(outDir, 0o755); err != nil {
+		return err
+	}
+
+	for _, f := range downloaded {
+		bts, err := base64.StdEncoding.DecodeString(f.FileBase64)
+		if err != nil {
+			return fmt.Errorf("cant decode file %s: %w", f.FileNameWithExtension, err)
+		}
+
+		path := filepath.Join(outDir, filepath.Base(f.FileNameWithExtension))
+		if err := os.WriteFile(path, bts, 0o644); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(w, "Saved %s\n", path)
+	}
+
+	return nil
+}
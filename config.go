@@ -0,0 +1,217 @@
+package nopaper
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LoadConfig reads yaml config file by path and validates it.
+// Unknown fields are reported as errors to catch typos early.
+func LoadConfig(path string) (Config, error) {
+	cfg, err := ReadConfigFile(path)
+	if err != nil {
+		return Config{}, err
+	}
+
+	err = cfg.Validate()
+	if err != nil {
+		return Config{}, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+// ReadConfigFile reads yaml config file like LoadConfig but does not validate it,
+// so config might be completed, e.g. by command line flags, and validated by Config.Validate or NewClient.
+func ReadConfigFile(path string) (Config, error) {
+	bts, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("cant read config file: %w", err)
+	}
+
+	cfg := Config{}
+
+	dec := yaml.NewDecoder(bytes.NewReader(bts))
+	dec.KnownFields(true)
+
+	err = dec.Decode(&cfg)
+	if err != nil {
+		return Config{}, fmt.Errorf("cant decode config file %s: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+// ConfigFromEnv reads config from environment variables and validates it.
+// Variables are named as yaml keys of Config in upper case with prefix,
+// e.g. NOPAPER_URL, NOPAPER_TOKEN_FILE, NOPAPER_RETRY_MAX for prefix "NOPAPER".
+func ConfigFromEnv(prefix string) (Config, error) {
+	env := envReader{prefix: prefix}
+	if prefix != "" && !strings.HasSuffix(prefix, "_") {
+		env.prefix += "_"
+	}
+
+	cfg := Config{
+		URL:                       env.string("URL"),
+		Environment:               Environment(env.string("ENVIRONMENT")),
+		ForbidProductionInTests:   env.bool("FORBID_PRODUCTION_IN_TESTS"),
+		Token:                     env.string("TOKEN"),
+		TokenFile:                 env.string("TOKEN_FILE"),
+		InsecureSkipVerify:        env.bool("INSECURE_SKIP_VERIFY"),
+		Timeout:                   env.duration("TIMEOUT"),
+		RetryMax:                  env.int("RETRY_MAX"),
+		RetryWait:                 env.duration("RETRY_WAIT"),
+		ProxyURL:                  env.string("PROXY_URL"),
+		CAFiles:                   env.list("CA_FILES"),
+		CAPEM:                     env.string("CA_PEM"),
+		ClientCertFile:            env.string("CLIENT_CERT_FILE"),
+		ClientKeyFile:             env.string("CLIENT_KEY_FILE"),
+		ClientCertPEM:             env.string("CLIENT_CERT_PEM"),
+		ClientKeyPEM:              env.string("CLIENT_KEY_PEM"),
+		MinTLSVersion:             env.string("MIN_TLS_VERSION"),
+		MaxIdleConns:              env.int("MAX_IDLE_CONNS"),
+		MaxIdleConnsPerHost:       env.int("MAX_IDLE_CONNS_PER_HOST"),
+		MaxConnsPerHost:           env.int("MAX_CONNS_PER_HOST"),
+		IdleConnTimeout:           env.duration("IDLE_CONN_TIMEOUT"),
+		DialTimeout:               env.duration("DIAL_TIMEOUT"),
+		TLSHandshakeTimeout:       env.duration("TLS_HANDSHAKE_TIMEOUT"),
+		ResponseHeaderTimeout:     env.duration("RESPONSE_HEADER_TIMEOUT"),
+		RateLimit:                 env.float("RATE_LIMIT"),
+		RateBurst:                 env.int("RATE_BURST"),
+		SMSCodeLength:             env.int("SMS_CODE_LENGTH"),
+		SMSCodeMismatchErrorCodes: env.list("SMS_CODE_MISMATCH_ERROR_CODES"),
+		IssuingTypes:              env.issuingTypes("ISSUING_TYPES"),
+	}
+
+	if env.err != nil {
+		return Config{}, env.err
+	}
+
+	err := cfg.Validate()
+	if err != nil {
+		return Config{}, fmt.Errorf("invalid config from environment: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// envReader reads typed environment variables and keeps the first parse error.
+type envReader struct {
+	prefix string
+	err    error
+}
+
+func (e *envReader) string(name string) string {
+	return strings.TrimSpace(os.Getenv(e.prefix + name))
+}
+
+// list reads comma separated values.
+func (e *envReader) list(name string) []string {
+	v := e.string(name)
+	if v == "" {
+		return nil
+	}
+
+	var res []string
+
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			res = append(res, item)
+		}
+	}
+
+	return res
+}
+
+// issuingTypes reads comma separated pairs like 1=pc-server,2=pc-sms.
+func (e *envReader) issuingTypes(name string) map[IssuingType]SignatureType {
+	items := e.list(name)
+	if len(items) == 0 {
+		return nil
+	}
+
+	res := make(map[IssuingType]SignatureType, len(items))
+
+	for _, item := range items {
+		k, v, ok := strings.Cut(item, "=")
+
+		i, err := strconv.Atoi(strings.TrimSpace(k))
+		if !ok || err != nil {
+			e.fail(name, item, fmt.Errorf("must be issuing_type=signature_type"))
+
+			return nil
+		}
+
+		res[IssuingType(i)] = SignatureType(strings.TrimSpace(v))
+	}
+
+	return res
+}
+
+func (e *envReader) bool(name string) bool {
+	v := e.string(name)
+	if v == "" {
+		return false
+	}
+
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		e.fail(name, v, err)
+	}
+
+	return b
+}
+
+func (e *envReader) int(name string) int {
+	v := e.string(name)
+	if v == "" {
+		return 0
+	}
+
+	i, err := strconv.Atoi(v)
+	if err != nil {
+		e.fail(name, v, err)
+	}
+
+	return i
+}
+
+func (e *envReader) float(name string) float64 {
+	v := e.string(name)
+	if v == "" {
+		return 0
+	}
+
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		e.fail(name, v, err)
+	}
+
+	return f
+}
+
+func (e *envReader) duration(name string) time.Duration {
+	v := e.string(name)
+	if v == "" {
+		return 0
+	}
+
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		e.fail(name, v, err)
+	}
+
+	return d
+}
+
+func (e *envReader) fail(name, value string, err error) {
+	if e.err == nil {
+		e.err = fmt.Errorf("invalid %s%s=%q: %w", e.prefix, name, value, err)
+	}
+}
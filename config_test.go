@@ -0,0 +1,161 @@
+package nopaper
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "nopaper.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestLoadConfig(t *testing.T) {
+	path := writeConfig(t, `
+environment: demo
+token_file: /run/secrets/nopaper-token
+insecure_skip_verify: true
+timeout: 30s
+retry_max: 3
+retry_wait: 500ms
+ca_files: [/etc/ssl/a.pem, /etc/ssl/b.pem]
+min_tls_version: "1.2"
+rate_limit: 2.5
+sms_code_length: 6
+issuing_types: {3: pc-sms}
+`)
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := Config{
+		Environment:        EnvDemo,
+		TokenFile:          "/run/secrets/nopaper-token",
+		InsecureSkipVerify: true,
+		Timeout:            30 * time.Second,
+		RetryMax:           3,
+		RetryWait:          500 * time.Millisecond,
+		CAFiles:            []string{"/etc/ssl/a.pem", "/etc/ssl/b.pem"},
+		MinTLSVersion:      "1.2",
+		RateLimit:          2.5,
+		SMSCodeLength:      6,
+		IssuingTypes:       map[IssuingType]SignatureType{3: SignatureTypeSMS},
+	}
+
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("LoadConfig() = %+v, want %+v", cfg, want)
+	}
+}
+
+func TestLoadConfigErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		err     string
+	}{
+		{"unknown field", "url: https://np.example\ntoken: x\ntokn: y\n", "field tokn not found"},
+		{"bad duration", "url: https://np.example\ntoken: x\ntimeout: soon\n", "cant decode"},
+		{"api suffix", "url: https://np.example/partner-api/api/v2/external\ntoken: x\n", "must exclude"},
+		{"bad scheme", "url: ftp://np.example\ntoken: x\n", "scheme must be http or https"},
+		{"no url", "token: x\n", "url is required"},
+		{"no token", "url: https://np.example\n", "token, token_file or token source is required"},
+		{"two tokens", "url: https://np.example\ntoken: x\ntoken_file: /tmp/x\n", "mutually exclusive"},
+		{"demo mismatch", "environment: demo\nurl: https://np.example\ntoken: x\n", "does not match"},
+		{"production without url", "environment: production\ntoken: x\n", "url is required for production"},
+		{"issuing type", "url: https://np.example\ntoken: x\nissuing_types: {1: pc-unknown}\n", "unknown signature type"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadConfig(writeConfig(t, tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("LoadConfig() error = %v, want %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestReadConfigFileDoesNotValidate(t *testing.T) {
+	cfg, err := ReadConfigFile(writeConfig(t, "url: https://np.example\n"))
+	if err != nil {
+		t.Fatalf("ReadConfigFile() error = %v", err)
+	}
+
+	cfg.Token = "x"
+
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("NP_URL", "https://np.example")
+	t.Setenv("NP_TOKEN", "secret")
+	t.Setenv("NP_RETRY_MAX", "2")
+	t.Setenv("NP_RETRY_WAIT", "1s")
+	t.Setenv("NP_INSECURE_SKIP_VERIFY", "true")
+	t.Setenv("NP_CA_FILES", "/a.pem, /b.pem,")
+	t.Setenv("NP_RATE_LIMIT", "0.5")
+	t.Setenv("NP_ISSUING_TYPES", "1=pc-server, 2=pc-sms")
+
+	cfg, err := ConfigFromEnv("NP")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := Config{
+		URL:                "https://np.example",
+		Token:              "secret",
+		RetryMax:           2,
+		RetryWait:          time.Second,
+		InsecureSkipVerify: true,
+		CAFiles:            []string{"/a.pem", "/b.pem"},
+		RateLimit:          0.5,
+		IssuingTypes:       map[IssuingType]SignatureType{1: SignatureTypeServer, 2: SignatureTypeSMS},
+	}
+
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("ConfigFromEnv() = %+v, want %+v", cfg, want)
+	}
+}
+
+func TestConfigFromEnvErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		key   string
+		value string
+		err   string
+	}{
+		{"int", "NP_RETRY_MAX", "many", "invalid NP_RETRY_MAX"},
+		{"bool", "NP_INSECURE_SKIP_VERIFY", "sure", "invalid NP_INSECURE_SKIP_VERIFY"},
+		{"duration", "NP_TIMEOUT", "10", "invalid NP_TIMEOUT"},
+		{"float", "NP_RATE_LIMIT", "fast", "invalid NP_RATE_LIMIT"},
+		{"issuing types", "NP_ISSUING_TYPES", "pc-sms", "invalid NP_ISSUING_TYPES"},
+		{"url", "NP_URL", "np.example", "invalid config from environment"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("NP_URL", "https://np.example")
+			t.Setenv("NP_TOKEN", "secret")
+			t.Setenv(tt.key, tt.value)
+
+			_, err := ConfigFromEnv("NP_")
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("ConfigFromEnv() error = %v, want %q", err, tt.err)
+			}
+		})
+	}
+}
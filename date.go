@@ -0,0 +1,118 @@
+package nopaper
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// dateLayout is a date format of Nopaper api.
+const dateLayout = "2006-01-02"
+
+// Date is a calendar date without time and timezone, e.g. birth date.
+// It is marshaled to json as "2006-01-02", zero date is omitted with omitzero tag option.
+type Date struct {
+	Year  int
+	Month time.Month
+	Day   int
+}
+
+// NewDate creates date, values are normalized like in time.Date.
+func NewDate(year int, month time.Month, day int) Date {
+	return DateOf(time.Date(year, month, day, 0, 0, 0, 0, time.UTC))
+}
+
+// DateOf returns calendar date of time in its own location,
+// it is a migration helper for code that used time.Time for dates.
+func DateOf(t time.Time) Date {
+	if t.IsZero() {
+		return Date{}
+	}
+
+	y, m, d := t.Date()
+
+	return Date{Year: y, Month: m, Day: d}
+}
+
+// ParseDate parses date in 2006-01-02 or 02.01.2006 form.
+// Timestamps like 2006-01-02T15:04:05Z07:00 are accepted too,
+// their date part is taken as is without timezone conversion.
+func ParseDate(s string) (Date, error) {
+	s = strings.TrimSpace(s)
+
+	layout := dateLayout
+	switch {
+	case len(s) > len(dateLayout) && s[len(dateLayout)] == 'T':
+		s = s[:len(dateLayout)]
+	case strings.Contains(s, "."):
+		layout = "02.01.2006"
+	}
+
+	t, err := time.Parse(layout, s)
+	if err != nil {
+		return Date{}, fmt.Errorf("invalid date %q: %w", s, err)
+	}
+
+	return DateOf(t), nil
+}
+
+// MustParseDate is like ParseDate but panics on error.
+func MustParseDate(s string) Date {
+	d, err := ParseDate(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return d
+}
+
+// IsZero reports whether date is not set.
+func (d Date) IsZero() bool {
+	return d == Date{}
+}
+
+// Time returns midnight of date in location.
+func (d Date) Time(loc *time.Location) time.Time {
+	if d.IsZero() {
+		return time.Time{}
+	}
+
+	return time.Date(d.Year, d.Month, d.Day, 0, 0, 0, 0, loc)
+}
+
+// Before reports whether date d is before other.
+func (d Date) Before(other Date) bool {
+	return d.Time(time.UTC).Before(other.Time(time.UTC))
+}
+
+func (d Date) String() string {
+	if d.IsZero() {
+		return ""
+	}
+
+	return fmt.Sprintf("%04d-%02d-%02d", d.Year, d.Month, d.Day)
+}
+
+// MarshalText implements encoding.TextMarshaler, it is also used for json.
+func (d Date) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, it is also used for json.
+// Empty text is a zero date.
+func (d *Date) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*d = Date{}
+
+		return nil
+	}
+
+	parsed, err := ParseDate(string(text))
+	if err != nil {
+		return err
+	}
+
+	*d = parsed
+
+	return nil
+}
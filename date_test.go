@@ -0,0 +1,83 @@
+package nopaper
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Date
+		wantErr bool
+	}{
+		{in: "1990-05-17", want: NewDate(1990, time.May, 17)},
+		{in: " 17.05.1990 ", want: NewDate(1990, time.May, 17)},
+		{in: "1990-05-17T23:30:00-05:00", want: NewDate(1990, time.May, 17)},
+		{in: "1990-05-17T00:00:00Z", want: NewDate(1990, time.May, 17)},
+		{in: "", wantErr: true},
+		{in: "1990-13-01", wantErr: true},
+		{in: "31.02.1990", wantErr: true},
+		{in: "17/05/1990", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseDate(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseDate(%q) = %v, %v, want %v, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestDateJSON(t *testing.T) {
+	type profile struct {
+		BirthDate Date `json:"birthDate,omitzero"`
+	}
+
+	tests := []struct {
+		date Date
+		json string
+	}{
+		{date: NewDate(1990, time.May, 7), json: `{"birthDate":"1990-05-07"}`},
+		{date: Date{}, json: `{}`},
+	}
+
+	for _, tt := range tests {
+		bts, err := json.Marshal(profile{BirthDate: tt.date})
+		if err != nil || string(bts) != tt.json {
+			t.Errorf("Marshal(%v) = %s, %v, want %s", tt.date, bts, err, tt.json)
+		}
+
+		var got profile
+		if err := json.Unmarshal([]byte(tt.json), &got); err != nil || got.BirthDate != tt.date {
+			t.Errorf("Unmarshal(%s) = %v, %v, want %v", tt.json, got.BirthDate, err, tt.date)
+		}
+	}
+
+	var got profile
+	if err := json.Unmarshal([]byte(`{"birthDate":"1990-05-07T00:00:00+03:00"}`), &got); err != nil || got.BirthDate != NewDate(1990, time.May, 7) {
+		t.Errorf("Unmarshal of timestamp = %v, %v", got.BirthDate, err)
+	}
+
+	if err := json.Unmarshal([]byte(`{"birthDate":"not a date"}`), &got); err == nil {
+		t.Errorf("Unmarshal of invalid date returned no error")
+	}
+}
+
+func TestDateOf(t *testing.T) {
+	moscow := time.FixedZone("MSK", 3*60*60)
+
+	// Midnight in Moscow is the previous day in UTC, but date must not shift.
+	if got := DateOf(time.Date(1990, time.May, 7, 0, 30, 0, 0, moscow)); got != NewDate(1990, time.May, 7) {
+		t.Errorf("DateOf() = %v", got)
+	}
+
+	if !DateOf(time.Time{}).IsZero() {
+		t.Errorf("DateOf(zero time) is not zero")
+	}
+
+	if !NewDate(1990, time.May, 6).Before(NewDate(1990, time.May, 7)) {
+		t.Errorf("Before() = false")
+	}
+}
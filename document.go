@@ -9,6 +9,9 @@ import (
 	"net/http"
 
 	"github.com/google/uuid"
+
+	"github.com/KaymeKaydex/go-nopaper-client/phone"
+	"github.com/KaymeKaydex/go-nopaper-client/validation"
 )
 
 // DocumentRouteType represents the route type of document.
@@ -49,6 +52,7 @@ type CreateDraftDocumentRequest struct {
 }
 
 type RecipientInfo struct {
+	// UserPhone is normalized to 7XXXXXXXXXX form, see phone.Parse.
 	UserPhone  string `json:"userPhone,omitempty"`
 	CompanyInn string `json:"companyInn,omitempty"`
 	ActionType int    `json:"actionType,omitempty"`
@@ -63,6 +67,34 @@ type CreateDraftDocumentResponse struct {
 // CreateDraftDocument - creates new draft document package.
 // Document for Nopaper is a chain of word or pdf files.
 func (c *Client) CreateDraftDocument(ctx context.Context, rawReq CreateDraftDocumentRequest) (int, error) {
+	// Copy recipients to keep caller request unchanged.
+	rawReq.RecipientInfoList = append([]RecipientInfo(nil), rawReq.RecipientInfoList...)
+
+	var errs validation.Errors
+
+	for i, r := range rawReq.RecipientInfoList {
+		field := fmt.Sprintf("recipientInfoList[%d].", i)
+
+		if r.UserPhone != "" {
+			number, err := phone.Parse(r.UserPhone)
+			errs.Add(field+"userPhone", err)
+
+			rawReq.RecipientInfoList[i].UserPhone = number.String()
+		}
+
+		if r.CompanyInn != "" {
+			errs.Add(field+"companyInn", validation.INN(r.CompanyInn))
+		}
+
+		if r.CompanyKpp != "" {
+			errs.Add(field+"companyKpp", validation.KPP(r.CompanyKpp))
+		}
+	}
+
+	if err := errs.Err(); err != nil {
+		return 0, err
+	}
+
 	body := bytes.NewBuffer(nil)
 	err := json.NewEncoder(body).Encode(rawReq)
 	if err != nil {
@@ -189,8 +221,10 @@ func (c *Client) StartSMSSignatureProcess(ctx context.Context, documentID int, s
 	return emptyResponseResult(resp)
 }
 
+// SignViaServerSignature signs document with server signature.
+// Request is never retried, since repeated request after network error might sign document twice.
 func (c *Client) SignViaServerSignature(ctx context.Context, documentID int, signatureID uuid.UUID) error {
-	req, err := http.NewRequestWithContext(ctx,
+	req, err := http.NewRequestWithContext(withoutRetry(ctx),
 		http.MethodPut,
 		c.url+fmt.Sprintf("/document/%d/sign/pc-server/%s", documentID, signatureID.String()),
 		nil)
@@ -208,9 +242,19 @@ func (c *Client) SignViaServerSignature(ctx context.Context, documentID int, sig
 	return emptyResponseResult(resp)
 }
 
+// ConfirmSMSSign confirms signature with sms code sent by StartSMSSignatureProcess.
+// Code is normalized by NormalizeSMSCode before sending, so pasted sms text is accepted.
+// It returns *SMSCodeError for invalid code and for code rejected by Nopaper
+// with one of Config.SMSCodeMismatchErrorCodes, other errors are returned as is.
+// Without configured codes rejected code is returned as unknown bad response error.
 func (c *Client) ConfirmSMSSign(ctx context.Context, documentID int, signatureID uuid.UUID, code string) error {
+	code, err := NormalizeSMSCode(code, c.smsCodeLength)
+	if err != nil {
+		return err
+	}
+
 	b := bytes.NewBuffer(nil)
-	err := json.NewEncoder(b).Encode(map[string]string{
+	err = json.NewEncoder(b).Encode(map[string]string{
 		"code": code,
 	})
 	if err != nil {
@@ -232,6 +276,22 @@ func (c *Client) ConfirmSMSSign(ctx context.Context, documentID int, signatureID
 		return err
 	}
 
+	if resp.StatusCode == http.StatusBadRequest {
+		// Bad response.
+		rawResp := &ErrorResponse{}
+
+		err = json.NewDecoder(resp.Body).Decode(rawResp)
+		if err != nil {
+			return fmt.Errorf("cant decode bad response with error: %w", err)
+		}
+
+		if _, ok := c.smsMismatchCodes[rawResp.Code]; ok {
+			return &SMSCodeError{Reason: SMSCodeRejected, NopaperCode: rawResp.Code}
+		}
+
+		return errorByCode(rawResp.Code)
+	}
+
 	return emptyResponseResult(resp)
 }
 
@@ -0,0 +1,255 @@
+package nopaper
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"path"
+
+	"github.com/google/uuid"
+
+	"github.com/KaymeKaydex/go-nopaper-client/phone"
+	"github.com/KaymeKaydex/go-nopaper-client/validation"
+)
+
+// DocumentBuilder accumulates draft document with files and validates it before any call to Nopaper.
+// Builder methods return builder itself, errors are reported by Validate, Build and Create.
+//
+//	id, err := c.NewDocument("Act 2024-05").
+//		Owner(ownerGUID).
+//		Company("7707083893", "773601001").
+//		Individual("+7 (912) 345-67-89").
+//		Route(nopaper.Consistent).
+//		DisableChange(true).
+//		File("act.pdf", pdf).
+//		Create(ctx)
+type DocumentBuilder struct {
+	c     *Client
+	req   CreateDraftDocumentRequest
+	files []FileInfo
+}
+
+// NewDocument returns builder of draft document with title.
+func (c *Client) NewDocument(title string) *DocumentBuilder {
+	return &DocumentBuilder{
+		c:   c,
+		req: CreateDraftDocumentRequest{Title: title},
+	}
+}
+
+// Title sets document title.
+func (b *DocumentBuilder) Title(title string) *DocumentBuilder {
+	b.req.Title = title
+
+	return b
+}
+
+// Owner sets user that creates document.
+func (b *DocumentBuilder) Owner(userGUID uuid.UUID) *DocumentBuilder {
+	b.req.UserID = &userGUID
+
+	return b
+}
+
+// Individual adds individual recipient by phone in any format supported by phone.Parse.
+func (b *DocumentBuilder) Individual(userPhone string) *DocumentBuilder {
+	return b.Recipient(RecipientInfo{UserPhone: userPhone})
+}
+
+// Company adds legal entity or individual entrepreneur recipient.
+// KPP is required for legal entity(10 digits INN) and must be empty otherwise.
+func (b *DocumentBuilder) Company(inn, kpp string) *DocumentBuilder {
+	return b.Recipient(RecipientInfo{CompanyInn: inn, CompanyKpp: kpp})
+}
+
+// Recipient adds recipient as is, e.g. with ActionType and SignType.
+func (b *DocumentBuilder) Recipient(r RecipientInfo) *DocumentBuilder {
+	b.req.RecipientInfoList = append(b.req.RecipientInfoList, r)
+
+	return b
+}
+
+// Route sets document route type.
+func (b *DocumentBuilder) Route(t DocumentRouteType) *DocumentBuilder {
+	b.req.DocumentRouteType = t
+
+	return b
+}
+
+// DisableChange prohibits route editing by document participants, see CreateDraftDocumentRequest.DisableChange.
+func (b *DocumentBuilder) DisableChange(disable bool) *DocumentBuilder {
+	b.req.DisableChange = disable
+
+	return b
+}
+
+// File adds file with name with extension, e.g. act.pdf.
+func (b *DocumentBuilder) File(nameWithExtension string, data []byte) *DocumentBuilder {
+	return b.FileBase64(nameWithExtension, base64.StdEncoding.EncodeToString(data))
+}
+
+// FileBase64 adds file with base64 encoded content.
+func (b *DocumentBuilder) FileBase64(nameWithExtension, filebase64 string) *DocumentBuilder {
+	b.files = append(b.files, FileInfo{FileNameWithExtension: nameWithExtension, Filebase64: filebase64})
+
+	return b
+}
+
+// Validate checks full document spec and returns validation.Errors with all invalid fields.
+// Builder is not changed, phones are normalized only in requests returned by Build.
+func (b *DocumentBuilder) Validate() error {
+	_, err := b.request()
+
+	return err
+}
+
+// request returns copy of draft request with normalized recipient phones and validates it.
+func (b *DocumentBuilder) request() (CreateDraftDocumentRequest, error) {
+	var errs validation.Errors
+
+	req := b.req
+	req.RecipientInfoList = append([]RecipientInfo(nil), b.req.RecipientInfoList...)
+
+	if req.Title == "" {
+		errs.Add("title", validation.ErrRequired)
+	}
+
+	if req.UserID != nil && *req.UserID == uuid.Nil {
+		errs.Add("userGuid", validation.ErrRequired)
+	}
+
+	switch req.DocumentRouteType {
+	case Consistent, Parallel:
+	case 0:
+		errs.Add("documentRouteType", validation.ErrRequired)
+	default:
+		errs.Add("documentRouteType", fmt.Errorf("%w: %d", ErrInvalidDocumentRouteType, req.DocumentRouteType))
+	}
+
+	if len(req.RecipientInfoList) == 0 {
+		errs.Add("recipientInfoList", validation.ErrRequired)
+	}
+
+	seen := make(map[RecipientInfo]struct{}, len(req.RecipientInfoList))
+
+	for i, r := range req.RecipientInfoList {
+		field := fmt.Sprintf("recipientInfoList[%d].", i)
+
+		if r.UserPhone == "" && r.CompanyInn == "" {
+			errs.Add(field+"userPhone", validation.ErrRequired)
+
+			continue
+		}
+
+		if r.UserPhone != "" {
+			number, err := phone.Parse(r.UserPhone)
+			if err != nil {
+				errs.Add(field+"userPhone", err)
+			} else {
+				req.RecipientInfoList[i].UserPhone = number.String()
+			}
+		}
+
+		if r.CompanyInn == "" && r.CompanyKpp != "" {
+			errs.Add(field+"companyInn", validation.ErrRequired)
+		}
+
+		if r.CompanyInn != "" {
+			errs.Add(field+"companyInn", validation.INN(r.CompanyInn))
+		}
+
+		errs.Add(field+"companyKpp", recipientKPP(r.CompanyInn, r.CompanyKpp))
+
+		key := req.RecipientInfoList[i]
+		if _, ok := seen[key]; ok {
+			errs.Add(field[:len(field)-1], ErrDuplicateRecipient)
+		}
+
+		seen[key] = struct{}{}
+	}
+
+	for i, f := range b.files {
+		field := fmt.Sprintf("files[%d].", i)
+
+		if f.FileNameWithExtension == "" {
+			errs.Add(field+"fileNameWithExtension", validation.ErrRequired)
+		} else if ext := path.Ext(f.FileNameWithExtension); ext == "" || ext == f.FileNameWithExtension {
+			errs.Add(field+"fileNameWithExtension", fmt.Errorf("%w: %s", ErrFileWithoutExtension, f.FileNameWithExtension))
+		}
+
+		if f.Filebase64 == "" {
+			errs.Add(field+"filebase64", validation.ErrRequired)
+		} else if _, err := base64.StdEncoding.DecodeString(f.Filebase64); err != nil {
+			errs.Add(field+"filebase64", err)
+		}
+	}
+
+	return req, errs.Err()
+}
+
+// recipientKPP returns the only error of recipient KPP, if any.
+// KPP is required for legal entity INN and must be empty for other valid INN,
+// only its format is checked if INN is invalid or empty.
+func recipientKPP(inn, kpp string) error {
+	switch {
+	case inn == "" || validation.INN(inn) != nil:
+		if kpp == "" {
+			return nil
+		}
+
+		return validation.KPP(kpp)
+	case validation.IsLegalEntityINN(inn):
+		if kpp == "" {
+			return validation.ErrRequired
+		}
+
+		return validation.KPP(kpp)
+	case kpp != "":
+		return fmt.Errorf("%w: kpp is set for not legal entity", validation.ErrInvalidKPP)
+	default:
+		return nil
+	}
+}
+
+// Build validates document and returns requests for CreateDraftDocument and AttachFile2Document.
+func (b *DocumentBuilder) Build() (CreateDraftDocumentRequest, []AttachFile2DocumentRequest, error) {
+	req, err := b.request()
+	if err != nil {
+		return CreateDraftDocumentRequest{}, nil, err
+	}
+
+	files := make([]AttachFile2DocumentRequest, 0, len(b.files))
+	for _, f := range b.files {
+		files = append(files, AttachFile2DocumentRequest{FileInfo: f})
+	}
+
+	return req, files, nil
+}
+
+// Create validates document, creates draft and attaches files to it, draft is not activated.
+//
+// Nopaper has no method to delete draft, so it is not rolled back if file attachment fails:
+// created document id is returned with error wrapping ErrFilesNotAttached,
+// so the failed file and the following ones might be attached to it by AttachFile2Document.
+// Document id is zero for any other error.
+func (b *DocumentBuilder) Create(ctx context.Context) (int, error) {
+	req, files, err := b.Build()
+	if err != nil {
+		return 0, err
+	}
+
+	documentID, err := b.c.CreateDraftDocument(ctx, req)
+	if err != nil {
+		return 0, fmt.Errorf("cant create draft document: %w", err)
+	}
+
+	for _, f := range files {
+		err = b.c.AttachFile2Document(ctx, documentID, f)
+		if err != nil {
+			return documentID, fmt.Errorf("%w: draft document %d, file %s: %w",
+				ErrFilesNotAttached, documentID, f.FileInfo.FileNameWithExtension, err)
+		}
+	}
+
+	return documentID, nil
+}
@@ -0,0 +1,271 @@
+package nopaper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/KaymeKaydex/go-nopaper-client/validation"
+)
+
+// fieldErrors returns invalid fields of validation error with count of errors of every field.
+func fieldErrors(err error) map[string]int {
+	fields := make(map[string]int)
+	for _, fe := range validation.Fields(err) {
+		fields[fe.Field]++
+	}
+
+	return fields
+}
+
+func TestDocumentBuilderValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		build  func(b *DocumentBuilder)
+		fields map[string]int
+	}{
+		{
+			name:   "valid",
+			build:  func(b *DocumentBuilder) {},
+			fields: map[string]int{},
+		},
+		{
+			name: "empty",
+			build: func(b *DocumentBuilder) {
+				b.req = CreateDraftDocumentRequest{UserID: &uuid.Nil}
+			},
+			fields: map[string]int{"title": 1, "userGuid": 1, "documentRouteType": 1, "recipientInfoList": 1},
+		},
+		{
+			name:   "invalid route",
+			build:  func(b *DocumentBuilder) { b.Route(3) },
+			fields: map[string]int{"documentRouteType": 1},
+		},
+		{
+			name:   "recipient without phone and inn",
+			build:  func(b *DocumentBuilder) { b.Recipient(RecipientInfo{ActionType: 1}) },
+			fields: map[string]int{"recipientInfoList[2].userPhone": 1},
+		},
+		{
+			name:   "invalid phone",
+			build:  func(b *DocumentBuilder) { b.Individual("+7 912") },
+			fields: map[string]int{"recipientInfoList[2].userPhone": 1},
+		},
+		{
+			name:   "legal entity without kpp",
+			build:  func(b *DocumentBuilder) { b.Company("7707083893", "") },
+			fields: map[string]int{"recipientInfoList[2].companyKpp": 1},
+		},
+		{
+			name:   "legal entity with invalid kpp",
+			build:  func(b *DocumentBuilder) { b.Company("7707083893", "77360100") },
+			fields: map[string]int{"recipientInfoList[2].companyKpp": 1},
+		},
+		{
+			name:   "individual entrepreneur",
+			build:  func(b *DocumentBuilder) { b.Company("500100732259", "") },
+			fields: map[string]int{},
+		},
+		{
+			name:   "individual entrepreneur with kpp",
+			build:  func(b *DocumentBuilder) { b.Company("500100732259", "773601001") },
+			fields: map[string]int{"recipientInfoList[2].companyKpp": 1},
+		},
+		{
+			name:   "individual entrepreneur with invalid kpp",
+			build:  func(b *DocumentBuilder) { b.Company("500100732259", "77360100") },
+			fields: map[string]int{"recipientInfoList[2].companyKpp": 1},
+		},
+		{
+			name:   "invalid inn with invalid kpp",
+			build:  func(b *DocumentBuilder) { b.Company("7707083894", "77360100") },
+			fields: map[string]int{"recipientInfoList[2].companyInn": 1, "recipientInfoList[2].companyKpp": 1},
+		},
+		{
+			name: "kpp without inn",
+			build: func(b *DocumentBuilder) {
+				b.Recipient(RecipientInfo{UserPhone: "89123456780", CompanyKpp: "773601001"})
+			},
+			fields: map[string]int{"recipientInfoList[2].companyInn": 1},
+		},
+		{
+			name:   "duplicate phone in another format",
+			build:  func(b *DocumentBuilder) { b.Individual("8 912 345 67 89") },
+			fields: map[string]int{"recipientInfoList[2]": 1},
+		},
+		{
+			name: "invalid files",
+			build: func(b *DocumentBuilder) {
+				b.File("", []byte("x")).File("act", []byte("x")).File(".pdf", []byte("x")).FileBase64("act.pdf", "%%%").FileBase64("act.pdf", "")
+			},
+			fields: map[string]int{
+				"files[1].fileNameWithExtension": 1,
+				"files[2].fileNameWithExtension": 1,
+				"files[3].fileNameWithExtension": 1,
+				"files[4].filebase64":            1,
+				"files[5].filebase64":            1,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		b := (&Client{}).NewDocument("Act").
+			Owner(uuid.New()).
+			Route(Consistent).
+			Individual("+7 (912) 345-67-89").
+			Company("7707083893", "773601001").
+			File("act.pdf", []byte("%PDF-"))
+		tt.build(b)
+
+		err := b.Validate()
+		if got := fieldErrors(err); !reflect.DeepEqual(got, tt.fields) {
+			t.Errorf("%s: Validate() invalid fields = %v, want %v, error: %v", tt.name, got, tt.fields, err)
+		}
+	}
+}
+
+func TestDocumentBuilderValidateKeepsBuilder(t *testing.T) {
+	b := (&Client{}).NewDocument("Act").Route(Parallel).Individual("+7 (912) 345-67-89")
+
+	for range 2 {
+		if err := b.Validate(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got := b.req.RecipientInfoList[0].UserPhone; got != "+7 (912) 345-67-89" {
+		t.Errorf("Validate() changed phone to %q", got)
+	}
+
+	req, files, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := req.RecipientInfoList[0].UserPhone; got != "79123456789" {
+		t.Errorf("Build() phone = %q, want normalized", got)
+	}
+
+	if len(files) != 0 {
+		t.Errorf("Build() files = %v", files)
+	}
+
+	// Request returned by Build is a copy.
+	req.RecipientInfoList[0].UserPhone = "changed"
+	if got := b.req.RecipientInfoList[0].UserPhone; got != "+7 (912) 345-67-89" {
+		t.Errorf("Build() request shares recipients with builder, phone = %q", got)
+	}
+}
+
+// documentServer is a fake of draft creation and file attachment, file named by failFile is rejected.
+type documentServer struct {
+	failFile string
+
+	mu       sync.Mutex
+	drafts   []CreateDraftDocumentRequest
+	attached []string
+}
+
+func (s *documentServer) client(t *testing.T) *Client {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST "+apiPath+"/document/draft", func(w http.ResponseWriter, r *http.Request) {
+		var req CreateDraftDocumentRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		s.mu.Lock()
+		s.drafts = append(s.drafts, req)
+		s.mu.Unlock()
+
+		_ = json.NewEncoder(w).Encode(CreateDraftDocumentResponse{DocumentID: 42})
+	})
+	mux.HandleFunc("POST "+apiPath+"/document/42/file", func(w http.ResponseWriter, r *http.Request) {
+		var req AttachFile2DocumentRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.FileInfo.FileNameWithExtension == s.failFile {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		s.mu.Lock()
+		s.attached = append(s.attached, req.FileInfo.FileNameWithExtension)
+		s.mu.Unlock()
+	})
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	c, err := NewClient(Config{URL: srv.URL, Token: "token"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return c
+}
+
+func TestDocumentBuilderCreate(t *testing.T) {
+	srv := &documentServer{}
+	c := srv.client(t)
+
+	id, err := c.NewDocument("Act").
+		Route(Consistent).
+		Individual("8 912 345 67 89").
+		File("act.pdf", []byte("%PDF-")).
+		File("appendix.pdf", []byte("%PDF-")).
+		Create(context.Background())
+	if err != nil || id != 42 {
+		t.Fatalf("Create() = %d, %v", id, err)
+	}
+
+	want := []RecipientInfo{{UserPhone: "79123456789"}}
+	if len(srv.drafts) != 1 || !reflect.DeepEqual(srv.drafts[0].RecipientInfoList, want) {
+		t.Errorf("drafts = %+v, want recipients %+v", srv.drafts, want)
+	}
+
+	sort.Strings(srv.attached)
+	if want := []string{"act.pdf", "appendix.pdf"}; !reflect.DeepEqual(srv.attached, want) {
+		t.Errorf("attached = %v, want %v", srv.attached, want)
+	}
+}
+
+func TestDocumentBuilderCreateErrors(t *testing.T) {
+	srv := &documentServer{failFile: "appendix.pdf"}
+	c := srv.client(t)
+
+	id, err := c.NewDocument("Act").Route(Consistent).Create(context.Background())
+	if id != 0 || len(validation.Fields(err)) == 0 {
+		t.Errorf("Create() of invalid document = %d, %v, want validation error", id, err)
+	}
+
+	if len(srv.drafts) != 0 {
+		t.Errorf("invalid document is sent: %+v", srv.drafts)
+	}
+
+	id, err = c.NewDocument("Act").
+		Route(Consistent).
+		Individual("89123456789").
+		File("act.pdf", []byte("%PDF-")).
+		File("appendix.pdf", []byte("%PDF-")).
+		File("last.pdf", []byte("%PDF-")).
+		Create(context.Background())
+	if id != 42 || !errors.Is(err, ErrFilesNotAttached) {
+		t.Errorf("Create() with failed file = %d, %v, want 42, %v", id, err, ErrFilesNotAttached)
+	}
+
+	if want := []string{"act.pdf"}; !reflect.DeepEqual(srv.attached, want) {
+		t.Errorf("attached = %v, want %v", srv.attached, want)
+	}
+}
@@ -0,0 +1,219 @@
+package nopaper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// Employee is a user that is an employee of partner company in Nopaper hub.
+type Employee struct {
+	UserGUID   uuid.UUID `json:"userGuid"`
+	UserPhone  string    `json:"userPhone"`
+	Name       string    `json:"name"`
+	Surname    string    `json:"surname"`
+	Patronymic string    `json:"patronymic"`
+}
+
+type ListEmployeesResponse struct {
+	EmployeeList []Employee `json:"employeeList"`
+}
+
+// ListEmployees returns employees of company.
+// Nopaper does not document listing of employees: GET /hub/employee/list and ListEmployeesResponse
+// are assumed by EmployUser and FireUser paths and user fields, check them on your stand.
+// Roster is expected in one response without pagination, so truncated roster is not detected;
+// check count of returned employees for large companies.
+func (c *Client) ListEmployees(ctx context.Context) ([]Employee, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url+"/hub/employee/list", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusOK {
+		// Good response.
+		rawResp := &ListEmployeesResponse{}
+
+		err = json.NewDecoder(resp.Body).Decode(rawResp)
+		if err != nil {
+			return nil, fmt.Errorf("cant decode good response with error: %w", err)
+		}
+
+		return rawResp.EmployeeList, nil
+	} else {
+		bts, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, fmt.Errorf("unknown status code from nopaper: %s with status: %s", resp.Status, string(bts))
+	}
+}
+
+// ReconcileEmployeesOptions - options of ReconcileEmployees.
+type ReconcileEmployeesOptions struct {
+	// DryRun only computes changes without applying them.
+	DryRun bool
+	// AllowEmpty allows empty desired list, i.e. firing the whole roster.
+	// Default MaxFires limit is not applied to empty desired list then.
+	AllowEmpty bool
+	// MaxFires limits count of fired users, changes over the limit are not applied at all.
+	// Zero means half of the roster but at least one, negative means no limit.
+	MaxFires int
+}
+
+// EmployeeChangeError is an error of hire or fire of one user.
+type EmployeeChangeError struct {
+	UserGUID uuid.UUID
+	// Hire is true for failed hire and false for failed fire.
+	Hire bool
+	Err  error
+}
+
+func (e EmployeeChangeError) Error() string {
+	action := "fire"
+	if e.Hire {
+		action = "hire"
+	}
+
+	return fmt.Sprintf("cant %s user %s: %v", action, e.UserGUID, e.Err)
+}
+
+func (e EmployeeChangeError) Unwrap() error {
+	return e.Err
+}
+
+// ReconcileEmployeesReport describes changes of employees roster.
+type ReconcileEmployeesReport struct {
+	DryRun bool
+	// Hired are users that are hired, or must be hired in dry run mode.
+	Hired []uuid.UUID
+	// Fired are users that are fired, or must be fired in dry run mode.
+	Fired []uuid.UUID
+	// Unchanged are users that are already employees.
+	Unchanged []uuid.UUID
+	// Failed are changes that returned errors, they are not included to Hired and Fired.
+	Failed []EmployeeChangeError
+}
+
+// ReconcileEmployees makes company employees roster equal to desired users:
+// users missing in roster are hired, users missing in desired list are fired.
+// All changes are tried, failed ones are reported in report and joined to returned error.
+// Empty desired list and fires over ReconcileEmployeesOptions.MaxFires are refused with ErrTooManyFires,
+// so broken source of desired list does not fire everybody.
+// Dry run returns report together with ErrTooManyFires that real run would return.
+func (c *Client) ReconcileEmployees(ctx context.Context, desired []uuid.UUID, opts ReconcileEmployeesOptions) (*ReconcileEmployeesReport, error) {
+	want := make(map[uuid.UUID]struct{}, len(desired))
+	for _, id := range desired {
+		if id == uuid.Nil {
+			return nil, fmt.Errorf("desired user uuid cant be nil")
+		}
+
+		want[id] = struct{}{}
+	}
+
+	roster, err := c.ListEmployees(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("cant list employees: %w", err)
+	}
+
+	current := make(map[uuid.UUID]struct{}, len(roster))
+	for _, e := range roster {
+		current[e.UserGUID] = struct{}{}
+	}
+
+	var toHire, toFire []uuid.UUID
+
+	report := &ReconcileEmployeesReport{DryRun: opts.DryRun}
+
+	seen := make(map[uuid.UUID]struct{}, len(desired))
+
+	for _, id := range desired {
+		// Skip duplicates in desired list.
+		if _, ok := seen[id]; ok {
+			continue
+		}
+
+		seen[id] = struct{}{}
+
+		if _, ok := current[id]; ok {
+			report.Unchanged = append(report.Unchanged, id)
+		} else {
+			toHire = append(toHire, id)
+		}
+	}
+
+	for _, e := range roster {
+		if _, ok := want[e.UserGUID]; !ok {
+			toFire = append(toFire, e.UserGUID)
+		}
+	}
+
+	refusal := checkFires(len(desired), len(roster), len(toFire), opts)
+
+	if opts.DryRun {
+		report.Hired, report.Fired = toHire, toFire
+
+		return report, refusal
+	}
+
+	if refusal != nil {
+		return nil, refusal
+	}
+
+	var errs []error
+
+	for _, id := range toHire {
+		if err := c.EmployUser(ctx, id); err != nil {
+			fail := EmployeeChangeError{UserGUID: id, Hire: true, Err: err}
+			report.Failed = append(report.Failed, fail)
+			errs = append(errs, fail)
+
+			continue
+		}
+
+		report.Hired = append(report.Hired, id)
+	}
+
+	for _, id := range toFire {
+		if err := c.FireUser(ctx, id); err != nil {
+			fail := EmployeeChangeError{UserGUID: id, Err: err}
+			report.Failed = append(report.Failed, fail)
+			errs = append(errs, fail)
+
+			continue
+		}
+
+		report.Fired = append(report.Fired, id)
+	}
+
+	return report, errors.Join(errs...)
+}
+
+// checkFires returns ErrTooManyFires if fires must not be applied by ReconcileEmployeesOptions.
+func checkFires(desired, roster, fires int, opts ReconcileEmployeesOptions) error {
+	if desired == 0 && !opts.AllowEmpty {
+		return fmt.Errorf("%w: desired list is empty, set AllowEmpty to fire everybody", ErrTooManyFires)
+	}
+
+	maxFires := opts.MaxFires
+	if maxFires == 0 && desired > 0 {
+		maxFires = max(roster/2, 1)
+	}
+
+	if maxFires > 0 && fires > maxFires {
+		return fmt.Errorf("%w: %d of %d employees must be fired, limit is %d", ErrTooManyFires, fires, roster, maxFires)
+	}
+
+	return nil
+}
@@ -0,0 +1,166 @@
+package nopaper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// fakeHub is an employees roster served over http, fires of failFire users fail.
+type fakeHub struct {
+	mu       sync.Mutex
+	roster   []uuid.UUID
+	hired    []uuid.UUID
+	fired    []uuid.UUID
+	failFire uuid.UUID
+}
+
+func (h *fakeHub) client(t *testing.T) *Client {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+
+		switch r.Method {
+		case http.MethodGet:
+			resp := ListEmployeesResponse{}
+			for _, id := range h.roster {
+				resp.EmployeeList = append(resp.EmployeeList, Employee{UserGUID: id})
+			}
+
+			_ = json.NewEncoder(w).Encode(resp)
+		case http.MethodPost:
+			var req struct {
+				UserGUID uuid.UUID `json:"userGuid"`
+			}
+
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			h.hired = append(h.hired, req.UserGUID)
+		case http.MethodDelete:
+			id := uuid.MustParse(r.URL.Query().Get("userGuid"))
+			if id == h.failFire {
+				w.WriteHeader(http.StatusInternalServerError)
+
+				return
+			}
+
+			h.fired = append(h.fired, id)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	c, err := NewClient(Config{URL: srv.URL, Token: "token"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return c
+}
+
+func newIDs(n int) []uuid.UUID {
+	ids := make([]uuid.UUID, n)
+	for i := range ids {
+		ids[i] = uuid.New()
+	}
+
+	return ids
+}
+
+func TestReconcileEmployees(t *testing.T) {
+	ids := newIDs(5)
+	hub := &fakeHub{roster: ids[:3]}
+
+	// ids[0] stays, ids[1] and ids[2] leave, ids[3] and ids[4] come, ids[3] is duplicated.
+	desired := []uuid.UUID{ids[0], ids[3], ids[4], ids[3]}
+
+	report, err := hub.client(t).ReconcileEmployees(context.Background(), desired, ReconcileEmployeesOptions{MaxFires: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !slices.Equal(report.Unchanged, ids[:1]) {
+		t.Errorf("Unchanged = %v, want %v", report.Unchanged, ids[:1])
+	}
+
+	if !slices.Equal(report.Hired, ids[3:]) || !slices.Equal(hub.hired, ids[3:]) {
+		t.Errorf("Hired = %v, hub hired %v, want %v", report.Hired, hub.hired, ids[3:])
+	}
+
+	if !slices.Equal(report.Fired, ids[1:3]) || !slices.Equal(hub.fired, ids[1:3]) {
+		t.Errorf("Fired = %v, hub fired %v, want %v", report.Fired, hub.fired, ids[1:3])
+	}
+}
+
+func TestReconcileEmployeesPartialFailure(t *testing.T) {
+	ids := newIDs(3)
+	hub := &fakeHub{roster: ids, failFire: ids[1]}
+
+	report, err := hub.client(t).ReconcileEmployees(context.Background(), ids[:1], ReconcileEmployeesOptions{MaxFires: 2})
+	if err == nil {
+		t.Fatal("error is nil")
+	}
+
+	if len(report.Failed) != 1 || report.Failed[0].UserGUID != ids[1] || report.Failed[0].Hire {
+		t.Errorf("Failed = %v, want fire of %s", report.Failed, ids[1])
+	}
+
+	if !slices.Equal(report.Fired, ids[2:]) {
+		t.Errorf("Fired = %v, want %v", report.Fired, ids[2:])
+	}
+}
+
+func TestReconcileEmployeesLimits(t *testing.T) {
+	ids := newIDs(6)
+
+	tests := []struct {
+		name    string
+		desired []uuid.UUID
+		opts    ReconcileEmployeesOptions
+		fired   int
+		refused bool
+	}{
+		{name: "default limit is half of roster", desired: ids[:3], fired: 3},
+		{name: "over default limit", desired: ids[:2], refused: true},
+		{name: "explicit limit", desired: ids[:2], opts: ReconcileEmployeesOptions{MaxFires: 4}, fired: 4},
+		{name: "over explicit limit", desired: ids[:4], opts: ReconcileEmployeesOptions{MaxFires: 1}, refused: true},
+		{name: "no limit", desired: ids[:1], opts: ReconcileEmployeesOptions{MaxFires: -1}, fired: 5},
+		{name: "empty desired", refused: true},
+		{name: "empty desired allowed", opts: ReconcileEmployeesOptions{AllowEmpty: true}, fired: 6},
+		{name: "empty desired allowed over explicit limit", opts: ReconcileEmployeesOptions{AllowEmpty: true, MaxFires: 5}, refused: true},
+	}
+
+	for _, tt := range tests {
+		for _, dryRun := range []bool{false, true} {
+			hub := &fakeHub{roster: ids}
+
+			opts := tt.opts
+			opts.DryRun = dryRun
+
+			report, err := hub.client(t).ReconcileEmployees(context.Background(), tt.desired, opts)
+
+			if refused := errors.Is(err, ErrTooManyFires); refused != tt.refused {
+				t.Errorf("%s, dry run %v: error = %v, want refused %v", tt.name, dryRun, err, tt.refused)
+			}
+
+			if !dryRun && tt.refused && (report != nil || len(hub.fired) > 0) {
+				t.Errorf("%s: refused run fired %v", tt.name, hub.fired)
+			}
+
+			if !tt.refused && len(report.Fired) != tt.fired {
+				t.Errorf("%s, dry run %v: fired %d, want %d", tt.name, dryRun, len(report.Fired), tt.fired)
+			}
+
+			if dryRun && (report == nil || len(hub.fired)+len(hub.hired) > 0) {
+				t.Errorf("%s: dry run report = %v, hub changes %v %v", tt.name, report, hub.hired, hub.fired)
+			}
+		}
+	}
+}
@@ -0,0 +1,228 @@
+package nopaper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/KaymeKaydex/go-nopaper-client/phone"
+)
+
+// EnsureUserResult reports what EnsureUser has done with user profile.
+type EnsureUserResult int
+
+const (
+	// UserUnchanged - user exists and profile matches request.
+	UserUnchanged EnsureUserResult = iota
+	// UserCreated - user is registered.
+	UserCreated
+	// UserUpdated - user exists and profile is patched.
+	UserUpdated
+)
+
+func (r EnsureUserResult) String() string {
+	switch r {
+	case UserUnchanged:
+		return "unchanged"
+	case UserCreated:
+		return "created"
+	case UserUpdated:
+		return "updated"
+	default:
+		return fmt.Sprintf("EnsureUserResult(%d)", int(r))
+	}
+}
+
+// EnsureUser returns user id by phone, registers user if it does not exist
+// and patches profile if name or passport data differs from request.
+// Calls for the same phone are serialized inside client; if other process registers
+// the same phone concurrently, registration error is resolved by one more lookup.
+func (c *Client) EnsureUser(ctx context.Context, rawReq RegisterUserRequest) (uuid.UUID, EnsureUserResult, error) {
+	number, err := phone.Parse(rawReq.UserPhone)
+	if err != nil {
+		return uuid.Nil, UserUnchanged, fmt.Errorf("invalid user phone: %w", err)
+	}
+
+	rawReq.UserPhone = number.String()
+
+	unlock, err := c.phoneLocks.Lock(ctx, rawReq.UserPhone)
+	if err != nil {
+		return uuid.Nil, UserUnchanged, err
+	}
+	defer unlock()
+
+	userID, err := c.GetUserUUIDByPhone(ctx, rawReq.UserPhone)
+	switch {
+	case errors.Is(err, ErrProfileByPhoneNotFound):
+		userID, err = c.RegisterUser(ctx, rawReq)
+		if err == nil {
+			return userID, UserCreated, nil
+		}
+
+		// User might be registered concurrently by other worker.
+		existingID, lookupErr := c.GetUserUUIDByPhone(ctx, rawReq.UserPhone)
+		if lookupErr != nil {
+			return uuid.Nil, UserUnchanged, fmt.Errorf("cant register user: %w", err)
+		}
+
+		userID = existingID
+	case err != nil:
+		return uuid.Nil, UserUnchanged, fmt.Errorf("cant get user by phone: %w", err)
+	}
+
+	current, err := c.GetUserProfile(ctx, userID)
+	if err != nil {
+		return userID, UserUnchanged, fmt.Errorf("cant get user profile: %w", err)
+	}
+
+	if !userInfoDiffers(rawReq.UserInfo, current.UserInfo) {
+		return userID, UserUnchanged, nil
+	}
+
+	err = c.PatchUserInfo(ctx, PatchUserInfoRequest{UserGUID: userID, UserInfo: rawReq.UserInfo})
+	if err != nil {
+		return userID, UserUnchanged, fmt.Errorf("cant patch user profile: %w", err)
+	}
+
+	return userID, UserUpdated, nil
+}
+
+// userInfoDiffers reports whether desired user info has filled fields that differ from current one.
+// Empty desired fields are not compared, because they are omitted in patch request.
+func userInfoDiffers(desired, current UserInfo) bool {
+	differs := func(want, got string) bool {
+		return want != "" && want != got
+	}
+
+	if differs(desired.Name, current.Name) ||
+		differs(desired.Surname, current.Surname) ||
+		differs(desired.Patronymic, current.Patronymic) ||
+		(desired.Gender != 0 && desired.Gender != current.Gender) ||
+		(!desired.BirthDate.IsZero() && desired.BirthDate != current.BirthDate) {
+		return true
+	}
+
+	want, got := desired.PassportData, current.PassportData
+	if want == nil {
+		return false
+	}
+
+	if got == nil {
+		got = &PassportData{}
+	}
+
+	return differs(want.Series, got.Series) ||
+		differs(want.Number, got.Number) ||
+		differs(want.IssuedBy, got.IssuedBy) ||
+		differs(want.IssuerDepartmentCode, got.IssuerDepartmentCode) ||
+		differs(want.BirthPlace, got.BirthPlace) ||
+		(!want.IssuingDate.IsZero() && want.IssuingDate != got.IssuingDate)
+}
+
+// EnsureSignatureMinValidity is a minimal remaining validity of certificate that EnsureSignature reuses.
+// Certificate that expires sooner is replaced with a new one.
+const EnsureSignatureMinValidity = 7 * 24 * time.Hour
+
+// EnsureSignatureWaitTimeout is a default limit of EnsureSignature wait for certificate,
+// it is used when WaitOptions.Timeout is zero.
+const EnsureSignatureWaitTimeout = 5 * time.Minute
+
+// ProfileIncompleteError is returned when certificate cant be issued because of not full user profile.
+// errors.Is matches it with ErrNotFullUserProfile.
+type ProfileIncompleteError struct {
+	UserGUID uuid.UUID
+	// MissingFields are json names of profile fields that must be filled, see UserProfile.MissingFieldsForCertificate.
+	MissingFields []string
+}
+
+func (e *ProfileIncompleteError) Error() string {
+	if len(e.MissingFields) == 0 {
+		return fmt.Sprintf("%s: user %s", ErrNotFullUserProfile, e.UserGUID)
+	}
+
+	return fmt.Sprintf("%s: user %s misses %s", ErrNotFullUserProfile, e.UserGUID, strings.Join(e.MissingFields, ", "))
+}
+
+func (e *ProfileIncompleteError) Unwrap() error {
+	return ErrNotFullUserProfile
+}
+
+// EnsureSignature returns usable certificate of signature type for user.
+// Available certificate that is valid for at least EnsureSignatureMinValidity is reused,
+// certificate that is already being issued is awaited, otherwise new one is created, activated and awaited.
+// Wait is limited by opts.Timeout or EnsureSignatureWaitTimeout.
+// It returns *ProfileIncompleteError if user profile is not full,
+// ErrIssuingTypeNotRegistered if no issuing type is bound to signature type
+// and ErrUnknownIssuingType if type of usable or pending certificate is unknown, see Config.IssuingTypes.
+func (c *Client) EnsureSignature(ctx context.Context, userGUID uuid.UUID, signatureType SignatureType, opts WaitOptions) (CertificateInfo, error) {
+	// Existing certificates cant be found without issuing type, so duplicates would be issued.
+	if err := CheckIssuingType(signatureType); err != nil {
+		return CertificateInfo{}, err
+	}
+
+	opts.Timeout = orDefault(opts.Timeout, EnsureSignatureWaitTimeout)
+
+	list, err := c.UserSignaturesList(ctx, userGUID)
+	if err != nil {
+		return CertificateInfo{}, fmt.Errorf("cant get certificates: %w", err)
+	}
+
+	if ci, ok := list.ActiveCertificate(signatureType); ok && ci.IsUsable(time.Now().Add(EnsureSignatureMinValidity)) {
+		return ci, nil
+	}
+
+	for _, ci := range list {
+		if ci.SignatureType() == "" && (ci.Status.IsPending() || ci.IsUsable(time.Now())) {
+			// It might be certificate of requested type, so new one is not issued.
+			return CertificateInfo{}, fmt.Errorf("%w %s of certificate %s", ErrUnknownIssuingType, ci.CustomData.IssuingType, ci.ID)
+		}
+	}
+
+	for _, ci := range list {
+		if ci.SignatureType() != signatureType || !ci.Status.IsPending() {
+			continue
+		}
+
+		if ci.Status == CertificateStatusTemplate {
+			if err := c.ActivateSignature(ctx, ci.ID); err != nil {
+				return CertificateInfo{}, fmt.Errorf("cant activate certificate: %w", err)
+			}
+		}
+
+		return c.WaitForCertificate(ctx, userGUID, ci.ID, opts)
+	}
+
+	return c.issueSignature(ctx, userGUID, signatureType, opts)
+}
+
+// issueSignature creates, activates and awaits new certificate.
+func (c *Client) issueSignature(ctx context.Context, userGUID uuid.UUID, signatureType SignatureType, opts WaitOptions) (CertificateInfo, error) {
+	certificateID, err := c.CreateSignature(ctx, CreateSignatureRequest{
+		UserGUID:                         userGUID,
+		ResponsiblePartyForAcceptanceAct: 2,
+		SignatureType:                    signatureType,
+	})
+	if errors.Is(err, ErrNotFullUserProfile) {
+		profileErr := &ProfileIncompleteError{UserGUID: userGUID}
+
+		if profile, err := c.GetUserProfile(ctx, userGUID); err == nil {
+			profileErr.MissingFields = profile.MissingFieldsForCertificate()
+		}
+
+		return CertificateInfo{}, profileErr
+	}
+
+	if err != nil {
+		return CertificateInfo{}, fmt.Errorf("cant create certificate: %w", err)
+	}
+
+	if err := c.ActivateSignature(ctx, certificateID); err != nil {
+		return CertificateInfo{}, fmt.Errorf("cant activate certificate: %w", err)
+	}
+
+	return c.WaitForCertificate(ctx, userGUID, certificateID, opts)
+}
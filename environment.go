@@ -0,0 +1,89 @@
+package nopaper
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Environment is a Nopaper stand that client works with.
+type Environment string
+
+func (e Environment) String() string {
+	return string(e)
+}
+
+const (
+	// EnvDemo is a demo stand for integration development.
+	EnvDemo Environment = "demo"
+	// EnvProduction is a production stand with legally significant documents.
+	// Its url is issued with partner token, so Config.URL is required for it.
+	EnvProduction Environment = "production"
+	// EnvCustom is any other stand, e.g. on-premise installation.
+	// Config.URL is required for it.
+	EnvCustom Environment = "custom"
+)
+
+// environmentURLs are base urls of stands with public url.
+var environmentURLs = map[Environment]string{
+	EnvDemo: "https://np-demo.abanking.ru",
+}
+
+// BaseURL returns base url of stand, it is empty for production and custom environments.
+func (e Environment) BaseURL() string {
+	return environmentURLs[e]
+}
+
+// APIPath returns path of partner api that is appended to base url.
+func (e Environment) APIPath() string {
+	return apiPath
+}
+
+// IsPreset reports whether environment is one of known stands with base url.
+func (e Environment) IsPreset() bool {
+	_, ok := environmentURLs[e]
+
+	return ok
+}
+
+// validate checks that environment is known and matches base url.
+func (e Environment) validate(baseURL string) error {
+	switch e {
+	case "", EnvCustom:
+		return nil
+	case EnvDemo:
+		if baseURL != "" && EnvironmentByURL(baseURL) != EnvDemo {
+			return fmt.Errorf("url %q does not match %s environment url %s", baseURL, e, e.BaseURL())
+		}
+	case EnvProduction:
+		if baseURL == "" {
+			return fmt.Errorf("url is required for %s environment", e)
+		}
+
+		if EnvironmentByURL(baseURL) == EnvDemo {
+			return fmt.Errorf("url %q is a demo url, but environment is %s", baseURL, e)
+		}
+	default:
+		return fmt.Errorf("unknown environment %q", e)
+	}
+
+	return nil
+}
+
+// EnvironmentByURL detects demo stand by its base url and returns EnvCustom for other ones,
+// production url is not known by client, so it is detected as EnvCustom too.
+func EnvironmentByURL(baseURL string) Environment {
+	u, err := url.Parse(strings.TrimSpace(baseURL))
+	if err != nil {
+		return EnvCustom
+	}
+
+	for env, envURL := range environmentURLs {
+		eu, _ := url.Parse(envURL)
+		if strings.EqualFold(u.Scheme, eu.Scheme) && strings.EqualFold(u.Host, eu.Host) {
+			return env
+		}
+	}
+
+	return EnvCustom
+}
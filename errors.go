@@ -17,8 +17,54 @@ var (
 	ErrRequestBodyWasNotConvertedToModel Error = "request body was not converted to model"
 	// ErrNotFullUserProfile - errors is caused by not full user profile.
 	ErrNotFullUserProfile Error = "cannot be created certificate without full name profile fl"
+	// ErrProductionInTest - client for stand that is not demo or explicit custom one is created
+	// while Config.ForbidProductionInTests is set.
+	ErrProductionInTest Error = "production environment is forbidden in tests"
+	// ErrUnknownTenant - there is no tenant with such key in ClientPool.
+	ErrUnknownTenant Error = "unknown tenant"
+	// ErrNoTenantInContext - context has no tenant key set by ContextWithTenant.
+	ErrNoTenantInContext Error = "no tenant in context"
+	// ErrCertificateInitializationFailed - certificate has InitializationError status.
+	ErrCertificateInitializationFailed Error = "certificate initialization failed"
+	// ErrCertificateBlocked - certificate has Blocked status.
+	ErrCertificateBlocked Error = "certificate is blocked"
+	// ErrCertificateRevoked - certificate has Revoked status.
+	ErrCertificateRevoked Error = "certificate is revoked"
+	// ErrCertificateNotFound - there is no certificate with such id.
+	ErrCertificateNotFound Error = "certificate not found"
+	// ErrCertificateAlreadyRevoked - RevokeSignature got conflict response, certificate is already revoked.
+	ErrCertificateAlreadyRevoked Error = "certificate is already revoked"
+	// ErrSMSResendTooEarly - sms code cant be resent during cooldown.
+	ErrSMSResendTooEarly Error = "sms code resend is too early"
+	// ErrSMSLockedOut - all sms codes and attempts of signing session are used.
+	ErrSMSLockedOut Error = "sms signing is locked out"
+	// ErrSMSMismatchCodesNotConfigured - SMSSigningSession cant tell wrong sms code from other errors,
+	// see Config.SMSCodeMismatchErrorCodes.
+	ErrSMSMismatchCodesNotConfigured Error = "sms code mismatch error codes are not configured"
+	// ErrInvalidSMSCode - sms code has invalid format or is rejected by Nopaper, see SMSCodeError.
+	ErrInvalidSMSCode Error = "invalid sms code"
+	// ErrBatchSignStopped - document is not signed because batch is stopped after error.
+	ErrBatchSignStopped Error = "batch sign is stopped after error"
+	// ErrNoActiveCertificate - user has no usable certificate of signature type.
+	ErrNoActiveCertificate Error = "user has no active certificate"
+	// ErrInvalidDocumentRouteType - document route type is neither Consistent nor Parallel.
+	ErrInvalidDocumentRouteType Error = "invalid document route type"
+	// ErrDuplicateRecipient - document has same recipient twice.
+	ErrDuplicateRecipient Error = "duplicate document recipient"
+	// ErrFilesNotAttached - draft document is created, but its files are not attached, see DocumentBuilder.Create.
+	ErrFilesNotAttached Error = "draft document is created, but files are not attached"
+	// ErrTooManyFires - ReconcileEmployees refused to fire more employees than allowed.
+	ErrTooManyFires Error = "too many employees to fire"
+	// ErrUnknownIssuingType - user has certificates which signature type is unknown, see Config.IssuingTypes.
+	ErrUnknownIssuingType Error = "unknown certificate issuing type"
+	// ErrIssuingTypeNotRegistered - no issuing type is bound to signature type, see Config.IssuingTypes.
+	ErrIssuingTypeNotRegistered Error = "issuing type of signature type is not registered"
+	// ErrFileWithoutExtension - file name has no extension.
+	ErrFileWithoutExtension Error = "file name has no extension"
 )
 
+// errorMap maps Nopaper error codes to sentinel errors.
+// Code of rejected sms code is not documented by Nopaper, so it is set by Config.SMSCodeMismatchErrorCodes.
 var errorMap = map[string]Error{
 	"NOPAPERPARTNER.10401":         ErrProfileByPhoneNotFound,
 	"NOPAPERPARTNERLIB.10401":      ErrProfileByPhoneNotFound,
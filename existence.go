@@ -7,10 +7,11 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
-	"strings"
-	"time"
 
 	"github.com/google/uuid"
+
+	"github.com/KaymeKaydex/go-nopaper-client/phone"
+	"github.com/KaymeKaydex/go-nopaper-client/validation"
 )
 
 type UserInfo struct {
@@ -18,18 +19,38 @@ type UserInfo struct {
 	Surname             string        `json:"surname,omitempty"`
 	Patronymic          string        `json:"patronymic,omitempty"`
 	IsShortTimePassword bool          `json:"isShortTimePassword"`
-	BirthDate           time.Time     `json:"birthDate,omitempty"`
+	BirthDate           Date          `json:"birthDate,omitzero"`
 	Gender              int           `json:"gender,omitempty"`
 	PassportData        *PassportData `json:"passportData,omitempty"`
 }
 
 type PassportData struct {
-	Series               string    `json:"series,omitempty"`
-	Number               string    `json:"number,omitempty"`
-	IssuedBy             string    `json:"issuedBy,omitempty"`
-	IssuingDate          time.Time `json:"issuingDate,omitempty"`
-	IssuerDepartmentCode string    `json:"issuerDepartmentCode,omitempty"`
-	BirthPlace           string    `json:"birthPlace,omitempty"`
+	Series               string `json:"series,omitempty"`
+	Number               string `json:"number,omitempty"`
+	IssuedBy             string `json:"issuedBy,omitempty"`
+	IssuingDate          Date   `json:"issuingDate,omitzero"`
+	IssuerDepartmentCode string `json:"issuerDepartmentCode,omitempty"`
+	BirthPlace           string `json:"birthPlace,omitempty"`
+}
+
+// validate checks user info fields that Nopaper accepts only in strict formats.
+func (u UserInfo) validate(errs *validation.Errors) {
+	p := u.PassportData
+	if p == nil {
+		return
+	}
+
+	if p.Series != "" {
+		errs.Add("passportData.series", validation.PassportSeries(p.Series))
+	}
+
+	if p.Number != "" {
+		errs.Add("passportData.number", validation.PassportNumber(p.Number))
+	}
+
+	if p.IssuerDepartmentCode != "" {
+		errs.Add("passportData.issuerDepartmentCode", validation.DepartmentCode(p.IssuerDepartmentCode))
+	}
 }
 
 type UserGUIDResponse struct {
@@ -41,10 +62,16 @@ type ErrorResponse struct {
 }
 
 // GetUserUUIDByPhone checks user existence in Nopaper system and returns user id if user exists.
-func (c *Client) GetUserUUIDByPhone(ctx context.Context, phone string) (uuid.UUID, error) {
+// Phone is normalized to 7XXXXXXXXXX form, see phone.Parse.
+func (c *Client) GetUserUUIDByPhone(ctx context.Context, userPhone string) (uuid.UUID, error) {
+	number, err := phone.Parse(userPhone)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("invalid user phone: %w", err)
+	}
+
 	v := url.Values{}
 
-	v.Add("userPhone", phone)
+	v.Add("userPhone", number.String())
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url+"/profile-fl/user-guid/by-phone", nil)
 	if err != nil {
@@ -84,18 +111,27 @@ func (c *Client) GetUserUUIDByPhone(ctx context.Context, phone string) (uuid.UUI
 }
 
 type RegisterUserRequest struct {
+	// UserPhone is normalized to 7XXXXXXXXXX form, see phone.Parse.
 	UserPhone string `json:"userPhone"`
 	Email     string `json:"email,omitempty"`
 	UserInfo
 }
 
 func (c *Client) RegisterUser(ctx context.Context, rawReq RegisterUserRequest) (uuid.UUID, error) {
-	if !strings.HasPrefix(rawReq.UserPhone, "7") {
-		return uuid.Nil, fmt.Errorf("user phone must be started from 7")
+	var errs validation.Errors
+
+	number, err := phone.Parse(rawReq.UserPhone)
+	errs.Add("userPhone", err)
+	rawReq.UserInfo.validate(&errs)
+
+	if err := errs.Err(); err != nil {
+		return uuid.Nil, err
 	}
 
+	rawReq.UserPhone = number.String()
+
 	body := bytes.NewBuffer(nil)
-	err := json.NewEncoder(body).Encode(rawReq)
+	err = json.NewEncoder(body).Encode(rawReq)
 	if err != nil {
 		return uuid.Nil, err
 	}
@@ -127,6 +163,97 @@ func (c *Client) RegisterUser(ctx context.Context, rawReq RegisterUserRequest) (
 	}
 }
 
+// UserProfile is a full profile-fl of individual.
+type UserProfile struct {
+	UserGUID  uuid.UUID `json:"userGuid"`
+	UserPhone string    `json:"userPhone"`
+	Email     string    `json:"email"`
+	// IsVerified - user identity is verified, e.g. by passport check.
+	IsVerified bool `json:"isVerified"`
+	UserInfo
+}
+
+// MissingFieldsForCertificate returns json names of profile fields that must be filled
+// before certificate can be issued, otherwise CreateSignature fails with ErrNotFullUserProfile.
+func (p UserProfile) MissingFieldsForCertificate() []string {
+	var missing []string
+
+	check := func(field string, filled bool) {
+		if !filled {
+			missing = append(missing, field)
+		}
+	}
+
+	check("name", p.Name != "")
+	check("surname", p.Surname != "")
+	check("birthDate", !p.BirthDate.IsZero())
+
+	pd := p.PassportData
+	if pd == nil {
+		pd = &PassportData{}
+	}
+
+	check("passportData.series", pd.Series != "")
+	check("passportData.number", pd.Number != "")
+	check("passportData.issuedBy", pd.IssuedBy != "")
+	check("passportData.issuingDate", !pd.IssuingDate.IsZero())
+	check("passportData.issuerDepartmentCode", pd.IssuerDepartmentCode != "")
+	check("passportData.birthPlace", pd.BirthPlace != "")
+
+	return missing
+}
+
+// GetUserProfile returns profile-fl of user by id.
+func (c *Client) GetUserProfile(ctx context.Context, userID uuid.UUID) (*UserProfile, error) {
+	if userID == uuid.Nil {
+		return nil, fmt.Errorf("user uuid cant be nil")
+	}
+
+	v := url.Values{}
+
+	v.Add("userGuid", userID.String())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url+"/profile-fl", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.URL.RawQuery = v.Encode()
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusOK {
+		// Good response.
+		rawResp := &UserProfile{}
+
+		err = json.NewDecoder(resp.Body).Decode(rawResp)
+		if err != nil {
+			return nil, fmt.Errorf("cant decode good response with error: %w", err)
+		}
+
+		if rawResp.UserGUID == uuid.Nil {
+			rawResp.UserGUID = userID
+		}
+
+		return rawResp, nil
+	} else if resp.StatusCode == http.StatusBadRequest {
+		// Bad response.
+		rawResp := &ErrorResponse{}
+
+		err = json.NewDecoder(resp.Body).Decode(rawResp)
+		if err != nil {
+			return nil, fmt.Errorf("cant decode bad response with error: %w", err)
+		}
+
+		return nil, errorByCode(rawResp.Code)
+	} else {
+		return nil, fmt.Errorf("unknown status code from nopaper: %s", resp.Status)
+	}
+}
+
 type PatchUserInfoRequest struct {
 	UserGUID uuid.UUID `json:"userGuid"`
 	UserInfo
@@ -138,6 +265,14 @@ func (c *Client) PatchUserInfo(ctx context.Context, rawReq PatchUserInfoRequest)
 		return fmt.Errorf("user uuid cant be nil")
 	}
 
+	var errs validation.Errors
+
+	rawReq.UserInfo.validate(&errs)
+
+	if err := errs.Err(); err != nil {
+		return err
+	}
+
 	body := bytes.NewBuffer(nil)
 	err := json.NewEncoder(body).Encode(rawReq)
 	if err != nil {
@@ -2,4 +2,9 @@ module github.com/KaymeKaydex/go-nopaper-client
 
 go 1.24.1
 
-require github.com/google/uuid v1.6.0
+require (
+	github.com/go-pdf/fpdf v0.9.0
+	github.com/google/uuid v1.6.0
+	golang.org/x/time v0.5.0
+	gopkg.in/yaml.v3 v3.0.1
+)
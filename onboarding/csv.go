@@ -0,0 +1,232 @@
+package onboarding
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// Columns of input csv, header is required and columns might be in any order.
+// Only phone column is required.
+const (
+	ColumnPhone                  = "phone"
+	ColumnSurname                = "surname"
+	ColumnName                   = "name"
+	ColumnPatronymic             = "patronymic"
+	ColumnEmail                  = "email"
+	ColumnBirthDate              = "birth_date"
+	ColumnBirthPlace             = "birth_place"
+	ColumnPassportSeries         = "passport_series"
+	ColumnPassportNumber         = "passport_number"
+	ColumnPassportIssuedBy       = "passport_issued_by"
+	ColumnPassportIssuingDate    = "passport_issuing_date"
+	ColumnPassportDepartmentCode = "passport_department_code"
+)
+
+// Row is one contractor from input csv.
+type Row struct {
+	// Line is a line number in input file, header is the first line.
+	Line int
+
+	Phone                  string
+	Surname                string
+	Name                   string
+	Patronymic             string
+	Email                  string
+	BirthDate              string
+	BirthPlace             string
+	PassportSeries         string
+	PassportNumber         string
+	PassportIssuedBy       string
+	PassportIssuingDate    string
+	PassportDepartmentCode string
+}
+
+// ReadRows reads contractors from csv with header.
+// Both comma and semicolon (Excel default for russian locale) delimiters are supported,
+// UTF-8 BOM is skipped.
+func ReadRows(r io.Reader) ([]Row, error) {
+	br := bufio.NewReader(r)
+
+	if bom, err := br.Peek(3); err == nil && bytes.Equal(bom, []byte("\xef\xbb\xbf")) {
+		_, _ = br.Discard(3)
+	}
+
+	cr := csv.NewReader(br)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	// Peek returns available bytes for short files too.
+	first, _ := br.Peek(4096)
+	if header, _, _ := bytes.Cut(first, []byte("\n")); bytes.Count(header, []byte(";")) > bytes.Count(header, []byte(",")) {
+		cr.Comma = ';'
+	}
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("cant read csv header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, h := range header {
+		columns[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+
+	if _, ok := columns[ColumnPhone]; !ok {
+		return nil, fmt.Errorf("csv header has no %s column", ColumnPhone)
+	}
+
+	var rows []Row
+
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("cant read csv: %w", err)
+		}
+
+		// Reader skips blank lines, so line is taken from reader instead of counting records.
+		line, _ := cr.FieldPos(0)
+
+		get := func(column string) string {
+			i, ok := columns[column]
+			if !ok || i >= len(record) {
+				return ""
+			}
+
+			return strings.TrimSpace(record[i])
+		}
+
+		row := Row{
+			Line:                   line,
+			Phone:                  get(ColumnPhone),
+			Surname:                get(ColumnSurname),
+			Name:                   get(ColumnName),
+			Patronymic:             get(ColumnPatronymic),
+			Email:                  get(ColumnEmail),
+			BirthDate:              get(ColumnBirthDate),
+			BirthPlace:             get(ColumnBirthPlace),
+			PassportSeries:         get(ColumnPassportSeries),
+			PassportNumber:         get(ColumnPassportNumber),
+			PassportIssuedBy:       get(ColumnPassportIssuedBy),
+			PassportIssuingDate:    get(ColumnPassportIssuingDate),
+			PassportDepartmentCode: get(ColumnPassportDepartmentCode),
+		}
+
+		if row == (Row{Line: line}) {
+			// Skip empty lines exported by spreadsheets.
+			continue
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// resultHeader is a header of result csv.
+var resultHeader = []string{"line", "phone", "user_guid", "user_result", "certificate_id", "error_code", "error"}
+
+// ResultWriter writes results to csv, it is safe for concurrent use.
+type ResultWriter struct {
+	mu sync.Mutex
+	w  *csv.Writer
+}
+
+// NewResultWriter creates writer, header is written only if withHeader is set,
+// so results might be appended to existing file on resume.
+func NewResultWriter(w io.Writer, withHeader bool) (*ResultWriter, error) {
+	rw := &ResultWriter{w: csv.NewWriter(w)}
+
+	if withHeader {
+		if err := rw.w.Write(resultHeader); err != nil {
+			return nil, err
+		}
+	}
+
+	return rw, nil
+}
+
+// Write writes result and flushes it, so file is consistent if process is killed.
+func (rw *ResultWriter) Write(r Result) error {
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+
+	certificateID := ""
+	if r.CertificateID != uuid.Nil {
+		certificateID = r.CertificateID.String()
+	}
+
+	userGUID := ""
+	if r.UserGUID != uuid.Nil {
+		userGUID = r.UserGUID.String()
+	}
+
+	err := rw.w.Write([]string{
+		strconv.Itoa(r.Line), r.Phone, userGUID, r.UserResult, certificateID, r.ErrorCode, r.Error,
+	})
+	if err != nil {
+		return err
+	}
+
+	rw.w.Flush()
+
+	return rw.w.Error()
+}
+
+// ReadResults reads result csv written by ResultWriter.
+func ReadResults(r io.Reader) ([]Result, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = len(resultHeader)
+
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("cant read results csv: %w", err)
+	}
+
+	var results []Result
+
+	for i, rec := range records {
+		if i == 0 && rec[0] == resultHeader[0] {
+			continue
+		}
+
+		res := Result{
+			Phone:      rec[1],
+			UserResult: rec[3],
+			ErrorCode:  rec[5],
+			Error:      rec[6],
+		}
+
+		res.Line, err = strconv.Atoi(rec[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid line in results csv record %d: %w", i+1, err)
+		}
+
+		if rec[2] != "" {
+			if res.UserGUID, err = uuid.Parse(rec[2]); err != nil {
+				return nil, fmt.Errorf("invalid user_guid in results csv record %d: %w", i+1, err)
+			}
+		}
+
+		if rec[4] != "" {
+			if res.CertificateID, err = uuid.Parse(rec[4]); err != nil {
+				return nil, fmt.Errorf("invalid certificate_id in results csv record %d: %w", i+1, err)
+			}
+		}
+
+		results = append(results, res)
+	}
+
+	return results, nil
+}
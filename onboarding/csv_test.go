@@ -0,0 +1,171 @@
+package onboarding
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestReadRows(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    []Row
+		wantErr bool
+	}{
+		{
+			name: "comma",
+			in:   "phone,surname,name\n+7 912 345-67-89,Ivanov,Ivan\n",
+			want: []Row{{Line: 2, Phone: "+7 912 345-67-89", Surname: "Ivanov", Name: "Ivan"}},
+		},
+		{
+			name: "semicolon",
+			in:   "phone;surname;name\n89123456789;Ivanov;Ivan\n",
+			want: []Row{{Line: 2, Phone: "89123456789", Surname: "Ivanov", Name: "Ivan"}},
+		},
+		{
+			name: "semicolon with commas in values",
+			in:   "phone;birth_place;name\n89123456789;Moscow, Russia;Ivan\n",
+			want: []Row{{Line: 2, Phone: "89123456789", BirthPlace: "Moscow, Russia", Name: "Ivan"}},
+		},
+		{
+			name: "comma with quoted semicolons",
+			in:   "phone,birth_place\n89123456789,\"Moscow; Russia\"\n",
+			want: []Row{{Line: 2, Phone: "89123456789", BirthPlace: "Moscow; Russia"}},
+		},
+		{
+			name: "bom",
+			in:   "\xef\xbb\xbfphone;name\n89123456789;Ivan\n",
+			want: []Row{{Line: 2, Phone: "89123456789", Name: "Ivan"}},
+		},
+		{
+			name: "bom without rows",
+			in:   "\xef\xbb\xbfphone",
+		},
+		{
+			name: "header case, spaces and order",
+			in:   " Name , PHONE ,email\nIvan, 89123456789 ,ivan@example.com\r\n",
+			want: []Row{{Line: 2, Phone: "89123456789", Name: "Ivan", Email: "ivan@example.com"}},
+		},
+		{
+			name: "empty and short lines",
+			in:   "phone;name;email\n;;\n\n89123456789\n;Ivan\n",
+			want: []Row{{Line: 4, Phone: "89123456789"}, {Line: 5, Name: "Ivan"}},
+		},
+		{
+			name:    "no phone column",
+			in:      "name,email\nIvan,ivan@example.com\n",
+			wantErr: true,
+		},
+		{
+			name:    "empty file",
+			in:      "",
+			wantErr: true,
+		},
+		{
+			name:    "broken quotes",
+			in:      "phone,name\n89123456789,\"Ivan\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		got, err := ReadRows(strings.NewReader(tt.in))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: ReadRows() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+
+			continue
+		}
+
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: ReadRows() = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestResults(t *testing.T) {
+	userGUID := uuid.New()
+	certificateID := uuid.New()
+
+	results := []Result{
+		{Line: 2, Phone: "79123456789", UserGUID: userGUID, UserResult: "found", CertificateID: certificateID},
+		{Line: 3, Phone: "79123456780", UserGUID: userGUID, UserResult: "created", ErrorCode: CodeCertificateError, Error: "timeout, retry later"},
+		{Line: 4, Phone: "912", ErrorCode: CodeInvalidPhone, Error: "invalid length"},
+	}
+
+	var buf bytes.Buffer
+
+	w, err := NewResultWriter(&buf, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, r := range results[:2] {
+		if err := w.Write(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Resumed run appends results without header.
+	w, err = NewResultWriter(&buf, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := w.Write(results[2]); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ReadResults(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(got, results) {
+		t.Errorf("ReadResults() = %+v, want %+v", got, results)
+	}
+
+	want := map[string]struct{}{"79123456789": {}}
+	if done := Completed(got); !reflect.DeepEqual(done, want) {
+		t.Errorf("Completed() = %v, want %v", done, want)
+	}
+}
+
+func TestReadResults(t *testing.T) {
+	id := uuid.New()
+
+	tests := []struct {
+		name    string
+		in      string
+		want    []Result
+		wantErr bool
+	}{
+		{name: "empty", in: ""},
+		{name: "header only", in: "line,phone,user_guid,user_result,certificate_id,error_code,error\n"},
+		{
+			name: "without header",
+			in:   "2,79123456789,," + "found," + id.String() + ",,\n",
+			want: []Result{{Line: 2, Phone: "79123456789", UserResult: "found", CertificateID: id}},
+		},
+		{name: "invalid line", in: "x,79123456789,,,,,\n", wantErr: true},
+		{name: "invalid user guid", in: "2,79123456789,guid,,,,\n", wantErr: true},
+		{name: "invalid certificate id", in: "2,79123456789,,,id,,\n", wantErr: true},
+		{name: "wrong column count", in: "2,79123456789\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ReadResults(strings.NewReader(tt.in))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: ReadResults() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+
+			continue
+		}
+
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: ReadResults() = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,240 @@
+// Package onboarding registers contractors in bulk: it reads rows exported from spreadsheets,
+// finds or registers users, fills their profiles and issues certificates for them.
+package onboarding
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+
+	nopaper "github.com/KaymeKaydex/go-nopaper-client"
+	"github.com/KaymeKaydex/go-nopaper-client/phone"
+	"github.com/KaymeKaydex/go-nopaper-client/validation"
+)
+
+// Error codes of Result.
+const (
+	CodeInvalidPhone      = "invalid_phone"
+	CodeInvalidRow        = "invalid_row"
+	CodeInvalidData       = "invalid_data"
+	CodeUserError         = "user_error"
+	CodeProfileIncomplete = "profile_incomplete"
+	CodeCertificateError  = "certificate_error"
+	CodeCanceled          = "canceled"
+)
+
+// Result is a result of onboarding of one row.
+type Result struct {
+	Line  int
+	Phone string
+	// UserGUID is set if user is found or registered.
+	UserGUID uuid.UUID
+	// UserResult is nopaper.EnsureUserResult in text form.
+	UserResult    string
+	CertificateID uuid.UUID
+	// ErrorCode is empty for successfully onboarded row.
+	ErrorCode string
+	Error     string
+}
+
+// OK reports whether row is onboarded successfully.
+func (r Result) OK() bool {
+	return r.ErrorCode == ""
+}
+
+// Options - options of Run.
+type Options struct {
+	// Concurrency is a count of rows processed in parallel. Default is 1.
+	Concurrency int
+	// SignatureType is a type of issued certificate. Default is nopaper.SignatureTypeSMS.
+	SignatureType nopaper.SignatureType
+	// Wait limits wait for issued certificate of every row, see nopaper.EnsureSignature.
+	Wait nopaper.WaitOptions
+	// Skip contains normalized phones that are already onboarded, e.g. from previous run results.
+	Skip map[string]struct{}
+}
+
+// Completed returns phones of successful results for Options.Skip to resume interrupted run.
+func Completed(results []Result) map[string]struct{} {
+	done := make(map[string]struct{}, len(results))
+
+	for _, r := range results {
+		if r.OK() {
+			done[r.Phone] = struct{}{}
+		}
+	}
+
+	return done
+}
+
+// Run onboards rows and calls report for every processed row from worker goroutines.
+// Rows with phones from Options.Skip are not processed and not reported.
+// On context cancel rows that are not started are reported with CodeCanceled.
+// It returns error without processing any row if issuing type of Options.SignatureType is not registered,
+// otherwise every row would be registered and then fail with CodeCertificateError.
+func Run(ctx context.Context, c *nopaper.Client, rows []Row, opts Options, report func(Result)) error {
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 1
+	}
+
+	if opts.SignatureType == "" {
+		opts.SignatureType = nopaper.SignatureTypeSMS
+	}
+
+	if err := nopaper.CheckIssuingType(opts.SignatureType); err != nil {
+		return err
+	}
+
+	jobs := make(chan Row)
+
+	var wg sync.WaitGroup
+
+	for range opts.Concurrency {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for row := range jobs {
+				report(onboard(ctx, c, row, opts))
+			}
+		}()
+	}
+
+	for _, row := range rows {
+		if number, err := phone.Parse(row.Phone); err == nil {
+			if _, ok := opts.Skip[number.String()]; ok {
+				continue
+			}
+		}
+
+		if ctx.Err() != nil {
+			report(Result{Line: row.Line, Phone: row.Phone, ErrorCode: CodeCanceled, Error: ctx.Err().Error()})
+
+			continue
+		}
+
+		select {
+		case jobs <- row:
+		case <-ctx.Done():
+			report(Result{Line: row.Line, Phone: row.Phone, ErrorCode: CodeCanceled, Error: ctx.Err().Error()})
+		}
+	}
+
+	close(jobs)
+	wg.Wait()
+
+	return nil
+}
+
+// onboard processes one row: user lookup or registration, profile patch and certificate issue.
+// Usable certificate is reused, so rows might be processed again safely.
+func onboard(ctx context.Context, c *nopaper.Client, row Row, opts Options) Result {
+	res := Result{Line: row.Line, Phone: row.Phone}
+
+	fail := func(code string, err error) Result {
+		res.ErrorCode, res.Error = code, err.Error()
+
+		return res
+	}
+
+	number, err := phone.Parse(row.Phone)
+	if err != nil {
+		return fail(CodeInvalidPhone, err)
+	}
+
+	res.Phone = number.String()
+
+	req, err := registerRequest(row)
+	if err != nil {
+		return fail(CodeInvalidRow, err)
+	}
+
+	userID, userResult, err := c.EnsureUser(ctx, req)
+	res.UserGUID = userID
+
+	if err != nil {
+		if len(validation.Fields(err)) > 0 {
+			return fail(CodeInvalidData, err)
+		}
+
+		return fail(CodeUserError, err)
+	}
+
+	res.UserResult = userResult.String()
+
+	certificate, err := c.EnsureSignature(ctx, userID, opts.SignatureType, opts.Wait)
+	if err != nil {
+		if errors.Is(err, nopaper.ErrNotFullUserProfile) {
+			return fail(CodeProfileIncomplete, err)
+		}
+
+		return fail(CodeCertificateError, err)
+	}
+
+	res.CertificateID = certificate.ID
+
+	return res
+}
+
+// registerRequest converts row to register request.
+func registerRequest(row Row) (nopaper.RegisterUserRequest, error) {
+	req := nopaper.RegisterUserRequest{
+		UserPhone: row.Phone,
+		Email:     row.Email,
+		UserInfo: nopaper.UserInfo{
+			Name:       row.Name,
+			Surname:    row.Surname,
+			Patronymic: row.Patronymic,
+		},
+	}
+
+	var err error
+
+	if row.BirthDate != "" {
+		req.BirthDate, err = nopaper.ParseDate(row.BirthDate)
+		if err != nil {
+			return req, fmt.Errorf("%s: %w", ColumnBirthDate, err)
+		}
+	}
+
+	passport := nopaper.PassportData{
+		Series:               stripSpaces(row.PassportSeries),
+		Number:               stripSpaces(row.PassportNumber),
+		IssuedBy:             row.PassportIssuedBy,
+		IssuerDepartmentCode: departmentCode(row.PassportDepartmentCode),
+		BirthPlace:           row.BirthPlace,
+	}
+
+	if row.PassportIssuingDate != "" {
+		passport.IssuingDate, err = nopaper.ParseDate(row.PassportIssuingDate)
+		if err != nil {
+			return req, fmt.Errorf("%s: %w", ColumnPassportIssuingDate, err)
+		}
+	}
+
+	if passport != (nopaper.PassportData{}) {
+		req.PassportData = &passport
+	}
+
+	return req, nil
+}
+
+// stripSpaces removes spaces that spreadsheets keep in passport series like "45 06".
+func stripSpaces(s string) string {
+	return strings.Join(strings.Fields(s), "")
+}
+
+// departmentCode converts department code to XXX-XXX form, e.g. 770001 to 770-001.
+func departmentCode(s string) string {
+	s = stripSpaces(s)
+	if len(s) == 6 && !strings.Contains(s, "-") {
+		return s[:3] + "-" + s[3:]
+	}
+
+	return s
+}
@@ -0,0 +1,66 @@
+package onboarding
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	nopaper "github.com/KaymeKaydex/go-nopaper-client"
+)
+
+// newClient creates client that fails every request, rows in tests are never sent.
+func newClient(t *testing.T) *nopaper.Client {
+	t.Helper()
+
+	c, err := nopaper.NewClient(nopaper.Config{URL: "http://127.0.0.1:1", Token: "token"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return c
+}
+
+func TestRunIssuingTypeNotRegistered(t *testing.T) {
+	rows := []Row{{Line: 2, Phone: "89123456789"}}
+
+	err := Run(context.Background(), newClient(t), rows, Options{SignatureType: nopaper.SignatureTypeServer}, func(r Result) {
+		t.Errorf("row %d is processed", r.Line)
+	})
+	if !errors.Is(err, nopaper.ErrIssuingTypeNotRegistered) {
+		t.Errorf("Run() error = %v, want %v", err, nopaper.ErrIssuingTypeNotRegistered)
+	}
+}
+
+func TestRunSkip(t *testing.T) {
+	if err := nopaper.RegisterIssuingType(9101, nopaper.SignatureTypeSMS); err != nil {
+		t.Fatal(err)
+	}
+
+	rows := []Row{
+		{Line: 2, Phone: "+7 (912) 345-67-89"},
+		{Line: 3, Phone: "89123456780"},
+		{Line: 4, Phone: "invalid"},
+	}
+
+	// Canceled context lets check which rows are not skipped without requests.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var reported []int
+
+	err := Run(ctx, newClient(t), rows, Options{Skip: map[string]struct{}{"79123456789": {}}}, func(r Result) {
+		if r.ErrorCode != CodeCanceled {
+			t.Errorf("row %d error code = %q, want %q", r.Line, r.ErrorCode, CodeCanceled)
+		}
+
+		reported = append(reported, r.Line)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []int{3, 4}; !reflect.DeepEqual(reported, want) {
+		t.Errorf("reported rows = %v, want %v", reported, want)
+	}
+}
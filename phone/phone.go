@@ -0,0 +1,101 @@
+// Package phone parses russian phone numbers into 7XXXXXXXXXX form that Nopaper expects.
+package phone
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Error string
+
+func (e Error) String() string {
+	return string(e)
+}
+
+func (e Error) Error() string {
+	return e.String()
+}
+
+var (
+	ErrEmpty              Error = "phone is empty"
+	ErrInvalidCharacters  Error = "phone contains invalid characters"
+	ErrInvalidLength      Error = "phone must contain 10 or 11 digits"
+	ErrInvalidCountryCode Error = "phone must be started from +7 or 8"
+	// ErrInvalidAreaCode - russian area and mobile codes start with 3, 4, 8 or 9,
+	// so e.g. 7912345678 is a number with missed digit, not 9-less local number.
+	ErrInvalidAreaCode Error = "phone area code must start with 3, 4, 8 or 9"
+)
+
+// Number is a phone number in canonical 7XXXXXXXXXX form.
+type Number string
+
+func (n Number) String() string {
+	return string(n)
+}
+
+// Pretty returns number in human readable form, e.g. +7 (912) 345-67-89.
+func (n Number) Pretty() string {
+	s := string(n)
+	if len(s) != 11 {
+		return s
+	}
+
+	return fmt.Sprintf("+%s (%s) %s-%s-%s", s[:1], s[1:4], s[4:7], s[7:9], s[9:])
+}
+
+// Parse parses phone in common russian formats:
+// +7 (912) 345-67-89, 8 912 345 67 89, 89123456789, 9123456789, 7.912.345.67.89.
+// Spaces, dots, dashes and brackets are ignored, plus is allowed only in the beginning.
+func Parse(s string) (Number, error) {
+	raw := strings.TrimSpace(s)
+	if raw == "" {
+		return "", ErrEmpty
+	}
+
+	digits := make([]byte, 0, 11)
+
+	for i, r := range raw {
+		switch {
+		case r >= '0' && r <= '9':
+			digits = append(digits, byte(r))
+		case r == '+' && i == 0:
+		case r == ' ', r == '\u00a0', r == '-', r == '(', r == ')', r == '.':
+		default:
+			return "", fmt.Errorf("%w: %q", ErrInvalidCharacters, s)
+		}
+	}
+
+	plus := strings.HasPrefix(raw, "+")
+
+	switch len(digits) {
+	case 10:
+		if plus {
+			return "", fmt.Errorf("%w: %q", ErrInvalidCountryCode, s)
+		}
+
+		digits = append([]byte{'7'}, digits...)
+	case 11:
+		switch {
+		case digits[0] == '7':
+		case digits[0] == '8' && !plus:
+			digits[0] = '7'
+		default:
+			return "", fmt.Errorf("%w: %q", ErrInvalidCountryCode, s)
+		}
+	default:
+		return "", fmt.Errorf("%w: %q", ErrInvalidLength, s)
+	}
+
+	if !strings.ContainsRune("3489", rune(digits[1])) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidAreaCode, s)
+	}
+
+	return Number(digits), nil
+}
+
+// Normalize is a shortcut for Parse that returns string.
+func Normalize(s string) (string, error) {
+	n, err := Parse(s)
+
+	return string(n), err
+}
@@ -0,0 +1,59 @@
+package phone
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Number
+		err  error
+	}{
+		{in: "+7 (912) 345-67-89", want: "79123456789"},
+		{in: "8 912 345 67 89", want: "79123456789"},
+		{in: "89123456789", want: "79123456789"},
+		{in: "79123456789", want: "79123456789"},
+		{in: "9123456789", want: "79123456789"},
+		{in: "7.912.345.67.89", want: "79123456789"},
+		{in: " +7 912 345 6789 ", want: "79123456789"},
+		{in: "8 (812) 123-45-67", want: "78121234567"},
+		{in: "4951234567", want: "74951234567"},
+		{in: "", err: ErrEmpty},
+		{in: "   ", err: ErrEmpty},
+		{in: "+7 912 345 67 8x", err: ErrInvalidCharacters},
+		{in: "7+9123456789", err: ErrInvalidCharacters},
+		{in: "912345678", err: ErrInvalidLength},
+		{in: "791234567890", err: ErrInvalidLength},
+		{in: "+9123456789", err: ErrInvalidCountryCode},
+		{in: "+89123456789", err: ErrInvalidCountryCode},
+		{in: "19123456789", err: ErrInvalidCountryCode},
+		{in: "7912345678", err: ErrInvalidAreaCode},
+		{in: "77123456789", err: ErrInvalidAreaCode},
+		{in: "1234567890", err: ErrInvalidAreaCode},
+	}
+
+	for _, tt := range tests {
+		got, err := Parse(tt.in)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("Parse(%q) error = %v, want %v", tt.in, err, tt.err)
+
+			continue
+		}
+
+		if got != tt.want {
+			t.Errorf("Parse(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestNumberPretty(t *testing.T) {
+	if got := Number("79123456789").Pretty(); got != "+7 (912) 345-67-89" {
+		t.Errorf("Pretty() = %q", got)
+	}
+
+	if got := Number("123").Pretty(); got != "123" {
+		t.Errorf("Pretty() of invalid number = %q", got)
+	}
+}
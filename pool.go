@@ -0,0 +1,162 @@
+package nopaper
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Tenant is a legal entity with its own Nopaper partner token.
+type Tenant struct {
+	// Key identifies tenant in pool, e.g. company INN.
+	Key string
+	// Token, TokenFile and TokenSource are tenant credentials, exactly one of them is required.
+	Token       string
+	TokenFile   string
+	TokenSource TokenSource
+	// RateLimit and RateBurst override pool config limits for tenant when set.
+	RateLimit float64
+	RateBurst int
+	// MetricsLabels are added to pool config labels, "tenant" label is always set to Key.
+	MetricsLabels map[string]string
+}
+
+// ClientPool holds one Client per tenant.
+// All clients share one http transport and its connection pool.
+type ClientPool struct {
+	cfg       Config
+	transport *http.Transport
+
+	mu      sync.RWMutex
+	clients map[string]*Client
+}
+
+// NewClientPool creates pool of clients for tenants.
+// Config is shared by all tenants except credentials, rate limits and metrics labels,
+// so its token fields must be empty.
+func NewClientPool(cfg Config, tenants ...Tenant) (*ClientPool, error) {
+	if cfg.Token != "" || cfg.TokenFile != "" || cfg.TokenSource != nil {
+		return nil, fmt.Errorf("pool config cant contain token, tokens are set per tenant")
+	}
+
+	cfg.URL = strings.ReplaceAll(cfg.URL, " ", "")
+	cfg.URL = strings.TrimSuffix(cfg.URL, "/")
+
+	if err := cfg.validate(false); err != nil {
+		return nil, err
+	}
+
+	tr, err := newHTTPTransport(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &ClientPool{
+		cfg:       cfg,
+		transport: tr,
+		clients:   make(map[string]*Client, len(tenants)),
+	}
+
+	for _, t := range tenants {
+		if err := p.Add(t); err != nil {
+			return nil, err
+		}
+	}
+
+	return p, nil
+}
+
+// Add creates client for tenant, tenant with the same key is replaced.
+func (p *ClientPool) Add(t Tenant) error {
+	if t.Key == "" {
+		return fmt.Errorf("tenant key cant be empty")
+	}
+
+	cfg := p.cfg
+	cfg.Token, cfg.TokenFile, cfg.TokenSource = t.Token, t.TokenFile, t.TokenSource
+
+	if t.RateLimit != 0 {
+		cfg.RateLimit, cfg.RateBurst = t.RateLimit, t.RateBurst
+	}
+
+	cfg.MetricsLabels = make(map[string]string, len(p.cfg.MetricsLabels)+len(t.MetricsLabels)+1)
+	maps.Copy(cfg.MetricsLabels, p.cfg.MetricsLabels)
+	maps.Copy(cfg.MetricsLabels, t.MetricsLabels)
+	cfg.MetricsLabels["tenant"] = t.Key
+
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid tenant %s: %w", t.Key, err)
+	}
+
+	c, err := newClient(cfg, p.transport)
+	if err != nil {
+		return fmt.Errorf("cant create client for tenant %s: %w", t.Key, err)
+	}
+
+	p.mu.Lock()
+	p.clients[t.Key] = c
+	p.mu.Unlock()
+
+	return nil
+}
+
+// Remove removes tenant client from pool.
+func (p *ClientPool) Remove(key string) {
+	p.mu.Lock()
+	delete(p.clients, key)
+	p.mu.Unlock()
+}
+
+// Client returns client of tenant by key with full Nopaper API.
+func (p *ClientPool) Client(key string) (*Client, error) {
+	p.mu.RLock()
+	c, ok := p.clients[key]
+	p.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownTenant, key)
+	}
+
+	return c, nil
+}
+
+// ClientFromContext returns client of tenant that is set to context by ContextWithTenant.
+func (p *ClientPool) ClientFromContext(ctx context.Context) (*Client, error) {
+	key, ok := TenantFromContext(ctx)
+	if !ok {
+		return nil, ErrNoTenantInContext
+	}
+
+	return p.Client(key)
+}
+
+// Tenants returns sorted keys of pool tenants.
+func (p *ClientPool) Tenants() []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return slices.Sorted(maps.Keys(p.clients))
+}
+
+// CloseIdleConnections closes idle connections of shared transport.
+func (p *ClientPool) CloseIdleConnections() {
+	p.transport.CloseIdleConnections()
+}
+
+type tenantCtxKey struct{}
+
+// ContextWithTenant returns context with tenant key for ClientPool.ClientFromContext.
+func ContextWithTenant(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, tenantCtxKey{}, key)
+}
+
+// TenantFromContext returns tenant key that is set by ContextWithTenant.
+func TenantFromContext(ctx context.Context) (string, bool) {
+	key, ok := ctx.Value(tenantCtxKey{}).(string)
+
+	return key, ok && key != ""
+}
@@ -0,0 +1,166 @@
+package nopaper
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// defaultBatchSignConcurrency is a default count of parallel SignManyViaServer calls.
+const defaultBatchSignConcurrency = 4
+
+// DocumentSignature identifies signature of document.
+type DocumentSignature struct {
+	DocumentID  int
+	SignatureID uuid.UUID
+}
+
+// BatchSignOptions - options of SignManyViaServer.
+type BatchSignOptions struct {
+	// Concurrency is a count of documents signed in parallel. Default is 4.
+	// Requests are limited by Config.RateLimit too.
+	Concurrency int
+	// StopOnError stops dispatching new documents after the first failure.
+	StopOnError bool
+}
+
+// DocumentSignResult is a result of signing of one document.
+type DocumentSignResult struct {
+	DocumentSignature
+	// Err is nil for signed document.
+	Err error
+	// Skipped is true if document was not dispatched because of cancel or StopOnError.
+	Skipped bool
+}
+
+// BatchSignError is returned by SignManyViaServer if some documents are not signed.
+type BatchSignError struct {
+	Total   int
+	Failed  int
+	Skipped int
+	// Errors are errors of failed documents.
+	Errors []error
+}
+
+func (e *BatchSignError) Error() string {
+	return fmt.Sprintf("%d of %d documents are not signed: %d failed, %d skipped",
+		e.Failed+e.Skipped, e.Total, e.Failed, e.Skipped)
+}
+
+func (e *BatchSignError) Unwrap() []error {
+	return e.Errors
+}
+
+// detachedCallContext returns context of started call that is not canceled with ctx,
+// but keeps its deadline.
+func detachedCallContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	callCtx := context.WithoutCancel(ctx)
+
+	if deadline, ok := ctx.Deadline(); ok {
+		return context.WithDeadline(callCtx, deadline)
+	}
+
+	return context.WithCancel(callCtx)
+}
+
+// SignManyViaServer signs documents with server signature by bounded pool of workers.
+// Results are returned in order of items. On context cancel new documents are not dispatched
+// and workers waiting for rate limiter stop, but already started calls are finished
+// until context deadline, so their results are reliable.
+// Error is *BatchSignError if any document is not signed.
+func (c *Client) SignManyViaServer(ctx context.Context, items []DocumentSignature, opts BatchSignOptions) ([]DocumentSignResult, error) {
+	concurrency := orDefault(opts.Concurrency, defaultBatchSignConcurrency)
+
+	results := make([]DocumentSignResult, len(items))
+	for i, item := range items {
+		results[i] = DocumentSignResult{DocumentSignature: item, Skipped: true}
+	}
+
+	dispatchCtx, stop := context.WithCancelCause(ctx)
+	defer stop(nil)
+
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+
+	for range min(concurrency, len(items)) {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range jobs {
+				item := items[i]
+
+				if dispatchCtx.Err() != nil {
+					continue
+				}
+
+				// Rate limiter is awaited while batch still might be stopped, item stays skipped then.
+				// Limiter fails before deadline if wait would exceed it, so its error is kept as a reason.
+				if err := c.waitRate(dispatchCtx); err != nil {
+					results[i].Err = fmt.Errorf("document %d is not dispatched: %w", item.DocumentID, err)
+
+					continue
+				}
+
+				callCtx, cancel := detachedCallContext(ctx)
+
+				err := c.SignViaServerSignature(withReservedRate(callCtx), item.DocumentID, item.SignatureID)
+
+				cancel()
+
+				if err != nil {
+					err = fmt.Errorf("document %d: %w", item.DocumentID, err)
+
+					if opts.StopOnError {
+						stop(ErrBatchSignStopped)
+					}
+				}
+
+				results[i] = DocumentSignResult{DocumentSignature: item, Err: err}
+			}
+		}()
+	}
+
+dispatch:
+	for i := range items {
+		// Check cancel first, select below chooses randomly between ready cases.
+		if dispatchCtx.Err() != nil {
+			break
+		}
+
+		select {
+		case jobs <- i:
+		case <-dispatchCtx.Done():
+			break dispatch
+		}
+	}
+
+	close(jobs)
+	wg.Wait()
+
+	batchErr := &BatchSignError{Total: len(items)}
+
+	for i := range results {
+		switch {
+		case results[i].Skipped:
+			if results[i].Err == nil {
+				results[i].Err = fmt.Errorf("document %d is not dispatched: %w", results[i].DocumentID, context.Cause(dispatchCtx))
+			}
+
+			batchErr.Skipped++
+		case results[i].Err != nil:
+			batchErr.Failed++
+			batchErr.Errors = append(batchErr.Errors, results[i].Err)
+		}
+	}
+
+	if batchErr.Failed+batchErr.Skipped > 0 {
+		return results, batchErr
+	}
+
+	return results, nil
+}
@@ -0,0 +1,238 @@
+package nopaper
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// newSignServer returns client of server that signs documents by handle, it gets document id.
+func newSignServer(t *testing.T, cfg Config, handle func(documentID int) int) *Client {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, rest, _ := strings.Cut(r.URL.Path, "/document/")
+		id, _, _ := strings.Cut(rest, "/")
+
+		documentID, err := strconv.Atoi(id)
+		if err != nil || r.Method != http.MethodPut {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		w.WriteHeader(handle(documentID))
+	}))
+	t.Cleanup(srv.Close)
+
+	cfg.URL = srv.URL
+	cfg.Token = "token"
+
+	c, err := NewClient(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return c
+}
+
+func newDocumentSignatures(n int) []DocumentSignature {
+	items := make([]DocumentSignature, n)
+	for i := range items {
+		items[i] = DocumentSignature{DocumentID: i + 1, SignatureID: uuid.New()}
+	}
+
+	return items
+}
+
+func TestSignManyViaServerPartialFailure(t *testing.T) {
+	c := newSignServer(t, Config{}, func(documentID int) int {
+		if documentID == 3 {
+			return http.StatusInternalServerError
+		}
+
+		return http.StatusOK
+	})
+
+	items := newDocumentSignatures(6)
+
+	results, err := c.SignManyViaServer(context.Background(), items, BatchSignOptions{Concurrency: 3})
+
+	var batchErr *BatchSignError
+	if !errors.As(err, &batchErr) || batchErr.Failed != 1 || batchErr.Skipped != 0 || batchErr.Total != 6 {
+		t.Fatalf("error = %#v, want one failed document", err)
+	}
+
+	for i, r := range results {
+		if r.DocumentSignature != items[i] {
+			t.Errorf("result %d is for document %d", i, r.DocumentID)
+		}
+
+		if failed := r.Err != nil; failed != (r.DocumentID == 3) || r.Skipped {
+			t.Errorf("document %d: error = %v, skipped %v", r.DocumentID, r.Err, r.Skipped)
+		}
+	}
+}
+
+func TestSignManyViaServerStopOnError(t *testing.T) {
+	var calls atomic.Int32
+
+	c := newSignServer(t, Config{}, func(documentID int) int {
+		calls.Add(1)
+
+		if documentID == 2 {
+			return http.StatusInternalServerError
+		}
+
+		return http.StatusOK
+	})
+
+	results, err := c.SignManyViaServer(context.Background(), newDocumentSignatures(5), BatchSignOptions{Concurrency: 1, StopOnError: true})
+
+	var batchErr *BatchSignError
+	if !errors.As(err, &batchErr) || batchErr.Failed != 1 || batchErr.Skipped != 3 {
+		t.Fatalf("error = %v, want 1 failed and 3 skipped", err)
+	}
+
+	for _, r := range results[2:] {
+		if !r.Skipped || !errors.Is(r.Err, ErrBatchSignStopped) {
+			t.Errorf("document %d: error = %v, skipped %v, want stopped", r.DocumentID, r.Err, r.Skipped)
+		}
+	}
+
+	if calls.Load() != 2 {
+		t.Errorf("calls = %d, want 2", calls.Load())
+	}
+}
+
+func TestSignManyViaServerCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	started := make(chan int)
+	release := make(chan struct{})
+
+	c := newSignServer(t, Config{}, func(documentID int) int {
+		started <- documentID
+		<-release
+
+		return http.StatusOK
+	})
+
+	type batch struct {
+		results []DocumentSignResult
+		err     error
+	}
+
+	done := make(chan batch)
+
+	go func() {
+		results, err := c.SignManyViaServer(ctx, newDocumentSignatures(6), BatchSignOptions{Concurrency: 2})
+		done <- batch{results, err}
+	}()
+
+	<-started
+	<-started
+	cancel()
+	// In-flight calls are finished after cancel.
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+
+	b := <-done
+
+	var signed, skipped int
+
+	for _, r := range b.results {
+		switch {
+		case r.Skipped && errors.Is(r.Err, context.Canceled):
+			skipped++
+		case r.Err == nil:
+			signed++
+		default:
+			t.Errorf("document %d: error = %v", r.DocumentID, r.Err)
+		}
+	}
+
+	if signed != 2 || skipped != 4 {
+		t.Errorf("signed %d, skipped %d, want 2 and 4, error %v", signed, skipped, b.err)
+	}
+}
+
+func TestSignManyViaServerRateLimit(t *testing.T) {
+	var calls atomic.Int32
+
+	c := newSignServer(t, Config{RateLimit: 20}, func(int) int {
+		calls.Add(1)
+
+		return http.StatusOK
+	})
+
+	start := time.Now()
+
+	if _, err := c.SignManyViaServer(context.Background(), newDocumentSignatures(6), BatchSignOptions{Concurrency: 6}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The first call uses burst, the next five wait 50ms each. Reserved rate is not spent twice.
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond || elapsed > time.Second {
+		t.Errorf("elapsed %s, want about 250ms", elapsed)
+	}
+
+	if calls.Load() != 6 {
+		t.Errorf("calls = %d, want 6", calls.Load())
+	}
+}
+
+func TestSignManyViaServerCancelRateWait(t *testing.T) {
+	c := newSignServer(t, Config{RateLimit: 0.5}, func(int) int {
+		return http.StatusOK
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+
+	results, err := c.SignManyViaServer(ctx, newDocumentSignatures(4), BatchSignOptions{Concurrency: 4})
+
+	var batchErr *BatchSignError
+	if !errors.As(err, &batchErr) || batchErr.Skipped != 3 || batchErr.Failed != 0 {
+		t.Errorf("error = %v, want 3 skipped", err)
+	}
+
+	for _, r := range results {
+		if r.Skipped && (r.Err == nil || strings.Contains(r.Err.Error(), "%!")) {
+			t.Errorf("document %d: error = %v, want rate limiter reason", r.DocumentID, r.Err)
+		}
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("elapsed %s, waiting for rate limiter is not canceled", elapsed)
+	}
+}
+
+func TestSignViaServerSignatureIsNotRetried(t *testing.T) {
+	var calls atomic.Int32
+
+	c := newSignServer(t, Config{RetryMax: 3, RetryWait: time.Millisecond}, func(int) int {
+		calls.Add(1)
+
+		return http.StatusServiceUnavailable
+	})
+
+	if err := c.SignViaServerSignature(context.Background(), 1, uuid.New()); err == nil {
+		t.Error("error is nil")
+	}
+
+	if calls.Load() != 1 {
+		t.Errorf("calls = %d, want 1", calls.Load())
+	}
+}
@@ -99,37 +99,30 @@ func (c *Client) CreateSignature(ctx context.Context, rawReq CreateSignatureRequ
 
 // UserSignaturesListResponse - typed response for signature list method.
 type UserSignaturesListResponse struct {
-	CertificatePCServerInfoList []CertificateInfo `json:"certificateInfoList"`
+	CertificatePCServerInfoList CertificateList `json:"certificateInfoList"`
 }
 
 type CertificateInfo struct {
-	ID uuid.UUID `json:"certificateId"`
-	// Status info.
-	// status=1 (Template) — signature template.
-	// status=2 (Initialization) — signature initialization.
-	// status=3 (InitializationError) — signature initialization error.
-	// status=4 (Available) — signature is active.
-	// status=5 (Blocked) — signature is blocked.
-	// status=6 (Revoked) — signature is revoked.
-	Status                int       `json:"status"`
-	IssuedDateTimeUtc     time.Time `json:"issuedDateTimeUtc"`
-	ValidUntilDateTimeUtc time.Time `json:"validUntilDateTimeUtc"`
-	OwnerName             string    `json:"ownerName"`
+	ID                    uuid.UUID         `json:"certificateId"`
+	Status                CertificateStatus `json:"status"`
+	IssuedDateTimeUtc     time.Time         `json:"issuedDateTimeUtc"`
+	ValidUntilDateTimeUtc time.Time         `json:"validUntilDateTimeUtc"`
+	OwnerName             string            `json:"ownerName"`
 	// OwnerID is id of signature(certificate) owner.
 	OwnerID      uuid.UUID             `json:"ownerGuid"`
 	CustomData   CertificateCustomData `json:"customData"`
-	ProviderType int                   `json:"providerType"`
+	ProviderType ProviderType          `json:"providerType"`
 }
 
 type CertificateCustomData struct {
-	PCUserId    string `json:"PCUserId"`
-	SystemId    string `json:"SystemId"`
-	PublicKey   string `json:"PublicKey"`
-	IssuingType int    `json:"IssuingType"`
+	PCUserId    string      `json:"PCUserId"`
+	SystemId    string      `json:"SystemId"`
+	PublicKey   string      `json:"PublicKey"`
+	IssuingType IssuingType `json:"IssuingType"`
 }
 
 // UserSignaturesList - method that returns list of signatures for user.
-func (c *Client) UserSignaturesList(ctx context.Context, userID uuid.UUID) ([]CertificateInfo, error) {
+func (c *Client) UserSignaturesList(ctx context.Context, userID uuid.UUID) (CertificateList, error) {
 	if userID == uuid.Nil {
 		return nil, fmt.Errorf("user uuid cant be nil")
 	}
@@ -190,3 +183,92 @@ func (c *Client) ActivateSignature(ctx context.Context, certificateID uuid.UUID)
 
 	return emptyResponseResult(resp)
 }
+
+// CertificateReason is a reason code of certificate block or revocation, codes follow RFC 5280.
+type CertificateReason int
+
+const (
+	CertificateReasonUnspecified          CertificateReason = 0
+	CertificateReasonKeyCompromise        CertificateReason = 1
+	CertificateReasonAffiliationChanged   CertificateReason = 3
+	CertificateReasonSuperseded           CertificateReason = 4
+	CertificateReasonCessationOfOperation CertificateReason = 5
+	// CertificateReasonCertificateHold is a reason of temporary block.
+	CertificateReasonCertificateHold CertificateReason = 6
+)
+
+var certificateReasonNames = map[CertificateReason]string{
+	CertificateReasonUnspecified:          "Unspecified",
+	CertificateReasonKeyCompromise:        "KeyCompromise",
+	CertificateReasonAffiliationChanged:   "AffiliationChanged",
+	CertificateReasonSuperseded:           "Superseded",
+	CertificateReasonCessationOfOperation: "CessationOfOperation",
+	CertificateReasonCertificateHold:      "CertificateHold",
+}
+
+func (r CertificateReason) String() string {
+	return enumString(r, certificateReasonNames, "CertificateReason")
+}
+
+// BlockSignature temporary blocks certificate(signature), e.g. when user lost phone.
+// Blocked certificate might be unblocked by UnblockSignature.
+func (c *Client) BlockSignature(ctx context.Context, certificateID uuid.UUID, reason CertificateReason) error {
+	return c.changeSignatureState(ctx, certificateID, "block", &reason)
+}
+
+// UnblockSignature unblocks certificate(signature) blocked by BlockSignature.
+func (c *Client) UnblockSignature(ctx context.Context, certificateID uuid.UUID) error {
+	return c.changeSignatureState(ctx, certificateID, "unblock", nil)
+}
+
+// RevokeSignature revokes certificate(signature) permanently, e.g. on key compromise.
+func (c *Client) RevokeSignature(ctx context.Context, certificateID uuid.UUID, reason CertificateReason) error {
+	return c.changeSignatureState(ctx, certificateID, "revoke", &reason)
+}
+
+// changeSignatureState calls certificate action by the same path pattern as ActivateSignature,
+// reason is omitted if it is nil.
+func (c *Client) changeSignatureState(ctx context.Context, certificateID uuid.UUID, action string, reason *CertificateReason) error {
+	if certificateID == uuid.Nil {
+		return fmt.Errorf("certificate uuid cant be nil")
+	}
+
+	req, err := http.NewRequestWithContext(ctx,
+		http.MethodPatch, c.url+fmt.Sprintf("/certificate/pay-control/%s/%s", certificateID.String(), action),
+		nil)
+	if err != nil {
+		return err
+	}
+
+	if reason != nil {
+		v := url.Values{}
+
+		v.Add("reason", strconv.Itoa(int(*reason)))
+
+		req.URL.RawQuery = v.Encode()
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return fmt.Errorf("%w: %s", ErrCertificateNotFound, certificateID)
+	case resp.StatusCode == http.StatusConflict && action == "revoke":
+		return fmt.Errorf("%w: %s", ErrCertificateAlreadyRevoked, certificateID)
+	case resp.StatusCode == http.StatusBadRequest:
+		// Bad response.
+		rawResp := &ErrorResponse{}
+
+		err = json.NewDecoder(resp.Body).Decode(rawResp)
+		if err != nil {
+			return fmt.Errorf("cant decode bad response with error: %w", err)
+		}
+
+		return errorByCode(rawResp.Code)
+	}
+
+	return emptyResponseResult(resp)
+}
@@ -0,0 +1,79 @@
+package nopaper
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// ResolveSignatureID returns signature id that user signs documents with,
+// i.e. id for StartSMSSignatureProcess, ConfirmSMSSign and SignViaServerSignature.
+// Nopaper uses id of user certificate(signature) as signature id and does not expose document signing slots,
+// so active certificate of signature type is returned and document membership is checked by Nopaper on sign.
+// It returns ErrIssuingTypeNotRegistered if no issuing type is bound to signature type,
+// ErrNoActiveCertificate if user has no usable certificate of signature type
+// and ErrUnknownIssuingType if usable certificates have unknown type.
+func (c *Client) ResolveSignatureID(ctx context.Context, userGUID uuid.UUID, signatureType SignatureType) (uuid.UUID, error) {
+	if userGUID == uuid.Nil {
+		return uuid.Nil, fmt.Errorf("user uuid cant be nil")
+	}
+
+	if err := CheckIssuingType(signatureType); err != nil {
+		return uuid.Nil, err
+	}
+
+	certificates, err := c.UserSignaturesList(ctx, userGUID)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("cant get certificates: %w", err)
+	}
+
+	ci, ok := certificates.ActiveCertificate(signatureType)
+	if !ok {
+		if _, unknown := certificates.ActiveCertificate(""); unknown {
+			return uuid.Nil, fmt.Errorf("%w: user %s has active certificates of unknown type", ErrUnknownIssuingType, userGUID)
+		}
+
+		return uuid.Nil, fmt.Errorf("%w: user %s, type %s", ErrNoActiveCertificate, userGUID, signatureType)
+	}
+
+	return ci.ID, nil
+}
+
+// ResolveSignatureIDByPhone is like ResolveSignatureID but finds user by phone.
+func (c *Client) ResolveSignatureIDByPhone(ctx context.Context, userPhone string, signatureType SignatureType) (uuid.UUID, error) {
+	userGUID, err := c.GetUserUUIDByPhone(ctx, userPhone)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return c.ResolveSignatureID(ctx, userGUID, signatureType)
+}
+
+// SignAs signs document as user with signature type and returns used signature id.
+// Server signature signs document at once. For sms signature code is sent to user
+// and must be confirmed with ConfirmSMSSign and returned signature id.
+func (c *Client) SignAs(ctx context.Context, documentID int, userGUID uuid.UUID, method SignatureType) (uuid.UUID, error) {
+	switch method {
+	case SignatureTypeServer, SignatureTypeSMS:
+	default:
+		return uuid.Nil, fmt.Errorf("unknown signature type %q", method)
+	}
+
+	signatureID, err := c.ResolveSignatureID(ctx, userGUID, method)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	if method == SignatureTypeServer {
+		err = c.SignViaServerSignature(ctx, documentID, signatureID)
+	} else {
+		err = c.StartSMSSignatureProcess(ctx, documentID, signatureID)
+	}
+
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return signatureID, nil
+}
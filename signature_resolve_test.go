@@ -0,0 +1,54 @@
+package nopaper
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestResolveSignatureID(t *testing.T) {
+	const server IssuingType = 9031
+
+	if err := RegisterIssuingType(server, SignatureTypeServer); err != nil {
+		t.Fatal(err)
+	}
+
+	active := func(issuingType IssuingType) CertificateInfo {
+		return CertificateInfo{
+			ID:                    uuid.New(),
+			Status:                CertificateStatusAvailable,
+			ValidUntilDateTimeUtc: time.Now().AddDate(1, 0, 0),
+			CustomData:            CertificateCustomData{IssuingType: issuingType},
+		}
+	}
+
+	known := active(server)
+
+	tests := []struct {
+		name          string
+		list          CertificateList
+		signatureType SignatureType
+		id            uuid.UUID
+		err           error
+	}{
+		{name: "active certificate", list: CertificateList{active(9032), known}, signatureType: SignatureTypeServer, id: known.ID},
+		{name: "no certificate", signatureType: SignatureTypeServer, err: ErrNoActiveCertificate},
+		{name: "unknown type", list: CertificateList{active(9032)}, signatureType: SignatureTypeServer, err: ErrUnknownIssuingType},
+		{name: "type not registered", list: CertificateList{known}, signatureType: "pc-unregistered", err: ErrIssuingTypeNotRegistered},
+	}
+
+	for _, tt := range tests {
+		var creates atomic.Int32
+
+		c := newCertificateServer(t, tt.list, &creates)
+
+		id, err := c.ResolveSignatureID(context.Background(), uuid.New(), tt.signatureType)
+		if id != tt.id || !errors.Is(err, tt.err) {
+			t.Errorf("%s: ResolveSignatureID() = %s, %v, want %s, %v", tt.name, id, err, tt.id, tt.err)
+		}
+	}
+}
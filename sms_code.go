@@ -0,0 +1,101 @@
+package nopaper
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Lengths of sms code accepted when Config.SMSCodeLength is not set.
+const (
+	minSMSCodeLength = 4
+	maxSMSCodeLength = 8
+)
+
+// SMSCodeReason is a reason of sms code rejection.
+type SMSCodeReason string
+
+const (
+	// SMSCodeEmpty - code is empty.
+	SMSCodeEmpty SMSCodeReason = "empty"
+	// SMSCodeNoDigits - there are no digits in code.
+	SMSCodeNoDigits SMSCodeReason = "no_digits"
+	// SMSCodeWrongLength - code has wrong count of digits.
+	SMSCodeWrongLength SMSCodeReason = "wrong_length"
+	// SMSCodeAmbiguous - pasted text contains several codes.
+	SMSCodeAmbiguous SMSCodeReason = "ambiguous"
+	// SMSCodeRejected - Nopaper rejected code, e.g. it does not match the sent one.
+	SMSCodeRejected SMSCodeReason = "rejected"
+)
+
+// SMSCodeError is returned for invalid or rejected sms code.
+// errors.Is matches it with ErrInvalidSMSCode.
+type SMSCodeError struct {
+	Reason SMSCodeReason
+	// NopaperCode is an error code from Nopaper response for rejected code.
+	NopaperCode string
+}
+
+func (e *SMSCodeError) Error() string {
+	if e.NopaperCode != "" {
+		return fmt.Sprintf("%s: %s (%s)", ErrInvalidSMSCode, e.Reason, e.NopaperCode)
+	}
+
+	return fmt.Sprintf("%s: %s", ErrInvalidSMSCode, e.Reason)
+}
+
+func (e *SMSCodeError) Unwrap() error {
+	return ErrInvalidSMSCode
+}
+
+// digitGroups matches digits that might be separated by single spaces or dashes, e.g. 123 456.
+var digitGroups = regexp.MustCompile(`\d+(?:[ \-]\d+)*`)
+
+// NormalizeSMSCode extracts sms code from user input: whitespaces and dashes are removed
+// and code is found in pasted sms text like "Код подписания: 123456".
+// Length is an exact count of digits, zero means any count from 4 to 8.
+func NormalizeSMSCode(raw string, length int) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", &SMSCodeError{Reason: SMSCodeEmpty}
+	}
+
+	valid := func(code string) bool {
+		if length > 0 {
+			return len(code) == length
+		}
+
+		return len(code) >= minSMSCodeLength && len(code) <= maxSMSCodeLength
+	}
+
+	groups := digitGroups.FindAllString(raw, -1)
+	if len(groups) == 0 {
+		return "", &SMSCodeError{Reason: SMSCodeNoDigits}
+	}
+
+	var candidates []string
+
+	for _, g := range groups {
+		code := strings.Map(func(r rune) rune {
+			if unicode.IsDigit(r) {
+				return r
+			}
+
+			return -1
+		}, g)
+
+		if valid(code) {
+			candidates = append(candidates, code)
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		return "", &SMSCodeError{Reason: SMSCodeWrongLength}
+	case 1:
+		return candidates[0], nil
+	default:
+		return "", &SMSCodeError{Reason: SMSCodeAmbiguous}
+	}
+}
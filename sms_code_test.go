@@ -0,0 +1,101 @@
+package nopaper
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestNormalizeSMSCode(t *testing.T) {
+	tests := []struct {
+		in     string
+		length int
+		want   string
+		reason SMSCodeReason
+	}{
+		{in: "123456", want: "123456"},
+		{in: " 1234 ", want: "1234"},
+		{in: "123 456", want: "123456"},
+		{in: "123-456", want: "123456"},
+		{in: "Код подписания: 123456. Никому не сообщайте.", want: "123456"},
+		{in: "12345678", want: "12345678"},
+		{in: "123456", length: 6, want: "123456"},
+		{in: "Документ 42, код 123456", length: 6, want: "123456"},
+		{in: "", reason: SMSCodeEmpty},
+		{in: "   ", reason: SMSCodeEmpty},
+		{in: "abcdef", reason: SMSCodeNoDigits},
+		{in: "123", reason: SMSCodeWrongLength},
+		{in: "123456789", reason: SMSCodeWrongLength},
+		{in: "12345", length: 6, reason: SMSCodeWrongLength},
+		{in: "код 1234 или 5678", reason: SMSCodeAmbiguous},
+	}
+
+	for _, tt := range tests {
+		got, err := NormalizeSMSCode(tt.in, tt.length)
+
+		if tt.reason == "" {
+			if err != nil || got != tt.want {
+				t.Errorf("NormalizeSMSCode(%q, %d) = %q, %v, want %q", tt.in, tt.length, got, err, tt.want)
+			}
+
+			continue
+		}
+
+		var codeErr *SMSCodeError
+		if !errors.As(err, &codeErr) || codeErr.Reason != tt.reason {
+			t.Errorf("NormalizeSMSCode(%q, %d) error = %v, want reason %s", tt.in, tt.length, err, tt.reason)
+		}
+
+		if !errors.Is(err, ErrInvalidSMSCode) {
+			t.Errorf("NormalizeSMSCode(%q, %d) error %v does not match ErrInvalidSMSCode", tt.in, tt.length, err)
+		}
+	}
+}
+
+func TestConfirmSMSSignBadRequest(t *testing.T) {
+	tests := []struct {
+		body     string
+		mismatch bool
+		err      error
+	}{
+		{body: `{"code":"CODE.MISMATCH"}`, mismatch: true, err: ErrInvalidSMSCode},
+		{body: `{"code":"NOPAPERPARTNERAPI.CORE.41116"}`, err: ErrRequestBodyWasNotConvertedToModel},
+		{body: `{"code":"NOPAPERPARTNER.99999"}`},
+		{body: `not json`},
+	}
+
+	for _, tt := range tests {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(tt.body))
+		}))
+
+		c, err := NewClient(Config{URL: srv.URL, Token: "token", SMSCodeMismatchErrorCodes: []string{"CODE.MISMATCH"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = c.ConfirmSMSSign(context.Background(), 1, uuid.New(), "1234")
+
+		srv.Close()
+
+		if err == nil {
+			t.Errorf("body %s: error is nil", tt.body)
+
+			continue
+		}
+
+		var codeErr *SMSCodeError
+		if errors.As(err, &codeErr) != tt.mismatch {
+			t.Errorf("body %s: error = %v, mismatch %v", tt.body, err, tt.mismatch)
+		}
+
+		if tt.err != nil && !errors.Is(err, tt.err) {
+			t.Errorf("body %s: error = %v, want %v", tt.body, err, tt.err)
+		}
+	}
+}
@@ -0,0 +1,231 @@
+package nopaper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Defaults of SMSSigningSession limits.
+const (
+	DefaultSMSMaxAttempts    = 3
+	DefaultSMSMaxSends       = 5
+	DefaultSMSResendCooldown = time.Minute
+	DefaultSMSCodeTTL        = 5 * time.Minute
+)
+
+// SMSSigningState is a state of SMSSigningSession.
+type SMSSigningState string
+
+const (
+	// SMSSigningNew - code is not sent yet.
+	SMSSigningNew SMSSigningState = "new"
+	// SMSSigningAwaitingCode - code is sent and awaits confirmation.
+	SMSSigningAwaitingCode SMSSigningState = "awaiting_code"
+	// SMSSigningSigned - document is signed.
+	SMSSigningSigned SMSSigningState = "signed"
+	// SMSSigningLockedOut - all attempts and sends are used, session cant be continued.
+	SMSSigningLockedOut SMSSigningState = "locked_out"
+)
+
+// SMSConfirmOutcome is a result of SMSSigningSession.Confirm.
+type SMSConfirmOutcome string
+
+const (
+	// SMSConfirmSigned - code is accepted and document is signed.
+	SMSConfirmSigned SMSConfirmOutcome = "signed"
+	// SMSConfirmInvalidCode - code has invalid format, it is not sent and attempt is not counted.
+	SMSConfirmInvalidCode SMSConfirmOutcome = "invalid_code"
+	// SMSConfirmWrongCode - code is rejected, it might be entered again.
+	SMSConfirmWrongCode SMSConfirmOutcome = "wrong_code"
+	// SMSConfirmCodeExpired - code is expired or all attempts for it are used, code must be resent.
+	SMSConfirmCodeExpired SMSConfirmOutcome = "code_expired"
+	// SMSConfirmLockedOut - all attempts and sends are used.
+	SMSConfirmLockedOut SMSConfirmOutcome = "locked_out"
+)
+
+// ResendTooEarlyError is returned by SMSSigningSession.Resend during cooldown.
+// errors.Is matches it with ErrSMSResendTooEarly.
+type ResendTooEarlyError struct {
+	RetryAfter time.Duration
+}
+
+func (e *ResendTooEarlyError) Error() string {
+	return fmt.Sprintf("%s, retry after %s", ErrSMSResendTooEarly, e.RetryAfter.Round(time.Second))
+}
+
+func (e *ResendTooEarlyError) Unwrap() error {
+	return ErrSMSResendTooEarly
+}
+
+// SMSSigningSession tracks SMS signing of document by one signer between stateless calls,
+// e.g. between HTTP requests of web backend. It is serializable to JSON, so it might be stored anywhere.
+// Session is not safe for concurrent use.
+type SMSSigningSession struct {
+	DocumentID  int             `json:"documentId"`
+	SignatureID uuid.UUID       `json:"signatureId"`
+	State       SMSSigningState `json:"state"`
+	// Attempts is a count of wrong codes entered for the current code.
+	Attempts int `json:"attempts"`
+	// Sends is a count of sent codes.
+	Sends  int       `json:"sends"`
+	SentAt time.Time `json:"sentAt,omitzero"`
+
+	// MaxAttempts is a count of wrong codes allowed for one sent code, zero means DefaultSMSMaxAttempts.
+	MaxAttempts int `json:"maxAttempts"`
+	// MaxSends is a count of codes that might be sent in session including the first one,
+	// zero means DefaultSMSMaxSends.
+	MaxSends int `json:"maxSends"`
+	// ResendCooldown is a minimal time between sends.
+	ResendCooldown time.Duration `json:"resendCooldown"`
+	// CodeTTL is a time code is valid after send.
+	CodeTTL time.Duration `json:"codeTtl"`
+}
+
+// NewSMSSigningSession creates session with default limits, they might be changed before Start.
+func NewSMSSigningSession(documentID int, signatureID uuid.UUID) *SMSSigningSession {
+	return &SMSSigningSession{
+		DocumentID:     documentID,
+		SignatureID:    signatureID,
+		State:          SMSSigningNew,
+		MaxAttempts:    DefaultSMSMaxAttempts,
+		MaxSends:       DefaultSMSMaxSends,
+		ResendCooldown: DefaultSMSResendCooldown,
+		CodeTTL:        DefaultSMSCodeTTL,
+	}
+}
+
+// Start sends the first code.
+// It returns ErrSMSMismatchCodesNotConfigured before sending if Config.SMSCodeMismatchErrorCodes is empty,
+// since wrong code could not be told apart from other errors then.
+func (s *SMSSigningSession) Start(ctx context.Context, c *Client) error {
+	if s.State != SMSSigningNew {
+		return fmt.Errorf("sms signing session is already started, state: %s", s.State)
+	}
+
+	if len(c.smsMismatchCodes) == 0 {
+		return ErrSMSMismatchCodesNotConfigured
+	}
+
+	return s.send(ctx, c)
+}
+
+// Resend sends new code if cooldown is over, wrong attempts are reset.
+// It returns *ResendTooEarlyError during cooldown and ErrSMSLockedOut if sends are over,
+// the current code might still be confirmed then.
+func (s *SMSSigningSession) Resend(ctx context.Context, c *Client) error {
+	switch s.State {
+	case SMSSigningAwaitingCode:
+	case SMSSigningLockedOut:
+		return ErrSMSLockedOut
+	default:
+		return fmt.Errorf("sms code cant be resent in state %s", s.State)
+	}
+
+	if s.Sends >= s.maxSends() {
+		// Current code might be still valid, so session is locked by Confirm when it is used.
+		return ErrSMSLockedOut
+	}
+
+	if wait := time.Until(s.ResendAvailableAt()); wait > 0 {
+		return &ResendTooEarlyError{RetryAfter: wait}
+	}
+
+	return s.send(ctx, c)
+}
+
+// Confirm sends code to Nopaper and returns outcome.
+// For SMSConfirmInvalidCode it returns *SMSCodeError with the reason code is not accepted, e.g. wrong length.
+// For other outcomes error is returned only for failures that are not a code rejection, e.g. network errors.
+func (s *SMSSigningSession) Confirm(ctx context.Context, c *Client, code string) (SMSConfirmOutcome, error) {
+	switch s.State {
+	case SMSSigningSigned:
+		return SMSConfirmSigned, nil
+	case SMSSigningLockedOut:
+		return SMSConfirmLockedOut, nil
+	case SMSSigningAwaitingCode:
+	default:
+		return "", fmt.Errorf("sms code cant be confirmed in state %s", s.State)
+	}
+
+	if s.CodeExpired() || s.Attempts >= s.maxAttempts() {
+		return s.expiredOutcome(), nil
+	}
+
+	code, err := NormalizeSMSCode(code, c.smsCodeLength)
+	if err != nil {
+		return SMSConfirmInvalidCode, err
+	}
+
+	err = c.ConfirmSMSSign(ctx, s.DocumentID, s.SignatureID, code)
+
+	var codeErr *SMSCodeError
+
+	switch {
+	case err == nil:
+		s.State = SMSSigningSigned
+
+		return SMSConfirmSigned, nil
+	case errors.As(err, &codeErr) && codeErr.Reason == SMSCodeRejected:
+		s.Attempts++
+
+		if s.Attempts >= s.maxAttempts() {
+			return s.expiredOutcome(), nil
+		}
+
+		return SMSConfirmWrongCode, nil
+	default:
+		return "", err
+	}
+}
+
+// AttemptsLeft returns a count of codes that might be entered for the current code.
+func (s *SMSSigningSession) AttemptsLeft() int {
+	return max(s.maxAttempts()-s.Attempts, 0)
+}
+
+// CodeExpired reports whether current code is expired.
+func (s *SMSSigningSession) CodeExpired() bool {
+	return !s.SentAt.IsZero() && s.CodeTTL > 0 && time.Since(s.SentAt) > s.CodeTTL
+}
+
+// ResendAvailableAt returns time when code might be resent.
+func (s *SMSSigningSession) ResendAvailableAt() time.Time {
+	return s.SentAt.Add(s.ResendCooldown)
+}
+
+func (s *SMSSigningSession) maxAttempts() int {
+	return orDefault(s.MaxAttempts, DefaultSMSMaxAttempts)
+}
+
+func (s *SMSSigningSession) maxSends() int {
+	return orDefault(s.MaxSends, DefaultSMSMaxSends)
+}
+
+// expiredOutcome returns outcome for used code and locks session if no sends left.
+func (s *SMSSigningSession) expiredOutcome() SMSConfirmOutcome {
+	if s.Sends >= s.maxSends() {
+		s.State = SMSSigningLockedOut
+
+		return SMSConfirmLockedOut
+	}
+
+	return SMSConfirmCodeExpired
+}
+
+func (s *SMSSigningSession) send(ctx context.Context, c *Client) error {
+	err := c.StartSMSSignatureProcess(ctx, s.DocumentID, s.SignatureID)
+	if err != nil {
+		return err
+	}
+
+	s.State = SMSSigningAwaitingCode
+	s.Sends++
+	s.Attempts = 0
+	s.SentAt = time.Now()
+
+	return nil
+}
@@ -0,0 +1,143 @@
+package nopaper
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// newSMSServer returns client of server that accepts code 123456 and rejects others with CODE.MISMATCH.
+func newSMSServer(t *testing.T, cfg Config) *Client {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/confirm") {
+			w.WriteHeader(http.StatusOK)
+
+			return
+		}
+
+		body, _ := io.ReadAll(r.Body)
+
+		if strings.Contains(string(body), `"123456"`) {
+			w.WriteHeader(http.StatusOK)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"code":"CODE.MISMATCH"}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	cfg.URL = srv.URL
+	cfg.Token = "token"
+
+	c, err := NewClient(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return c
+}
+
+func TestSMSSigningSessionRequiresMismatchCodes(t *testing.T) {
+	c := newSMSServer(t, Config{})
+
+	s := NewSMSSigningSession(1, uuid.New())
+
+	if err := s.Start(context.Background(), c); !errors.Is(err, ErrSMSMismatchCodesNotConfigured) {
+		t.Errorf("Start() error = %v, want %v", err, ErrSMSMismatchCodesNotConfigured)
+	}
+
+	if s.State != SMSSigningNew || s.Sends != 0 {
+		t.Errorf("Start() state = %s, sends = %d, want code not sent", s.State, s.Sends)
+	}
+}
+
+func TestSMSSigningSessionConfirm(t *testing.T) {
+	ctx := context.Background()
+	c := newSMSServer(t, Config{SMSCodeMismatchErrorCodes: []string{"CODE.MISMATCH"}, SMSCodeLength: 6})
+
+	s := NewSMSSigningSession(1, uuid.New())
+	if err := s.Start(ctx, c); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		code    string
+		outcome SMSConfirmOutcome
+		reason  SMSCodeReason
+	}{
+		{"", SMSConfirmInvalidCode, SMSCodeEmpty},
+		{"12 34", SMSConfirmInvalidCode, SMSCodeWrongLength},
+		{"111111 or 222222", SMSConfirmInvalidCode, SMSCodeAmbiguous},
+		{"654321", SMSConfirmWrongCode, ""},
+		{"Код: 123 456", SMSConfirmSigned, ""},
+	}
+
+	for _, tt := range tests {
+		outcome, err := s.Confirm(ctx, c, tt.code)
+		if outcome != tt.outcome {
+			t.Errorf("Confirm(%q) = %s, want %s", tt.code, outcome, tt.outcome)
+		}
+
+		var codeErr *SMSCodeError
+
+		switch {
+		case tt.reason == "" && err != nil:
+			t.Errorf("Confirm(%q) error = %v", tt.code, err)
+		case tt.reason != "" && (!errors.As(err, &codeErr) || codeErr.Reason != tt.reason):
+			t.Errorf("Confirm(%q) error = %v, want reason %s", tt.code, err, tt.reason)
+		}
+	}
+
+	if s.Attempts != 1 {
+		t.Errorf("Attempts = %d, want only rejected code counted", s.Attempts)
+	}
+}
+
+func TestSMSSigningSessionZeroLimits(t *testing.T) {
+	ctx := context.Background()
+	c := newSMSServer(t, Config{SMSCodeMismatchErrorCodes: []string{"CODE.MISMATCH"}})
+
+	// Session restored from storage without limits uses defaults.
+	s := &SMSSigningSession{DocumentID: 1, SignatureID: uuid.New(), State: SMSSigningNew}
+	if err := s.Start(ctx, c); err != nil {
+		t.Fatal(err)
+	}
+
+	if left := s.AttemptsLeft(); left != DefaultSMSMaxAttempts {
+		t.Errorf("AttemptsLeft() = %d, want %d", left, DefaultSMSMaxAttempts)
+	}
+
+	for i := 1; i < DefaultSMSMaxAttempts; i++ {
+		if outcome, err := s.Confirm(ctx, c, "654321"); outcome != SMSConfirmWrongCode || err != nil {
+			t.Fatalf("Confirm() attempt %d = %s, %v, want %s", i, outcome, err, SMSConfirmWrongCode)
+		}
+	}
+
+	if outcome, _ := s.Confirm(ctx, c, "654321"); outcome != SMSConfirmCodeExpired {
+		t.Errorf("Confirm() last attempt = %s, want %s", outcome, SMSConfirmCodeExpired)
+	}
+
+	for i := 1; i < DefaultSMSMaxSends; i++ {
+		if err := s.Resend(ctx, c); err != nil {
+			t.Fatalf("Resend() %d error = %v", i, err)
+		}
+	}
+
+	if err := s.Resend(ctx, c); !errors.Is(err, ErrSMSLockedOut) {
+		t.Errorf("Resend() over limit error = %v, want %v", err, ErrSMSLockedOut)
+	}
+
+	if outcome, err := s.Confirm(ctx, c, "123456"); outcome != SMSConfirmSigned || err != nil {
+		t.Errorf("Confirm() last code = %s, %v, want %s", outcome, err, SMSConfirmSigned)
+	}
+}
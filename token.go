@@ -0,0 +1,143 @@
+package nopaper
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TokenSource provides Nopaper auth token, it is consulted before every request.
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// TokenRefresher might be implemented by TokenSource to drop cached token
+// after Nopaper rejected it with 401 status.
+type TokenRefresher interface {
+	Refresh(ctx context.Context) error
+}
+
+// StaticTokenSource returns TokenSource that always returns the same token.
+func StaticTokenSource(token string) TokenSource {
+	return staticTokenSource(token)
+}
+
+type staticTokenSource string
+
+func (s staticTokenSource) Token(context.Context) (string, error) {
+	return string(s), nil
+}
+
+// TokenSourceFunc is a callback based TokenSource, e.g. reading token from a vault.
+// It is called before every request and is not told about rejected tokens,
+// use CachedTokenSource to cache token and refetch it after 401.
+type TokenSourceFunc func(ctx context.Context) (string, error)
+
+func (f TokenSourceFunc) Token(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// CachedTokenSource caches token fetched by callback, e.g. from a vault.
+// Token is fetched again when ttl is over and after Nopaper rejected it with 401 status.
+type CachedTokenSource struct {
+	fetch func(ctx context.Context) (string, error)
+	ttl   time.Duration
+
+	mu        sync.Mutex
+	token     string
+	fetchedAt time.Time
+}
+
+// NewCachedTokenSource creates CachedTokenSource, zero ttl means token is cached until it is rejected.
+func NewCachedTokenSource(fetch func(ctx context.Context) (string, error), ttl time.Duration) *CachedTokenSource {
+	return &CachedTokenSource{fetch: fetch, ttl: ttl}
+}
+
+// Token returns cached token or fetches new one.
+func (s *CachedTokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != "" && (s.ttl <= 0 || time.Since(s.fetchedAt) < s.ttl) {
+		return s.token, nil
+	}
+
+	token, err := s.fetch(ctx)
+	if err != nil {
+		return "", fmt.Errorf("cant fetch token: %w", err)
+	}
+
+	if token == "" {
+		return "", fmt.Errorf("fetched token is empty")
+	}
+
+	s.token, s.fetchedAt = token, time.Now()
+
+	return s.token, nil
+}
+
+// Refresh drops cached token, so the next Token call fetches new one.
+func (s *CachedTokenSource) Refresh(context.Context) error {
+	s.mu.Lock()
+	s.token = ""
+	s.mu.Unlock()
+
+	return nil
+}
+
+// FileTokenSource reads token from file and rereads it when file is changed,
+// so tokens from mounted secrets are rotated without client rebuild.
+type FileTokenSource struct {
+	path string
+
+	mu      sync.Mutex
+	token   string
+	modTime time.Time
+	size    int64
+}
+
+// NewFileTokenSource creates FileTokenSource for file by path.
+func NewFileTokenSource(path string) *FileTokenSource {
+	return &FileTokenSource{path: path}
+}
+
+// Token returns token from file, file is reread only if its modification time or size is changed.
+func (s *FileTokenSource) Token(context.Context) (string, error) {
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return "", fmt.Errorf("cant stat token file: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != "" && info.ModTime().Equal(s.modTime) && info.Size() == s.size {
+		return s.token, nil
+	}
+
+	bts, err := os.ReadFile(s.path)
+	if err != nil {
+		return "", fmt.Errorf("cant read token file: %w", err)
+	}
+
+	token := strings.TrimSpace(string(bts))
+	if token == "" {
+		return "", fmt.Errorf("token file %s is empty", s.path)
+	}
+
+	s.token, s.modTime, s.size = token, info.ModTime(), info.Size()
+
+	return s.token, nil
+}
+
+// Refresh drops cached token, so the next Token call rereads file.
+func (s *FileTokenSource) Refresh(context.Context) error {
+	s.mu.Lock()
+	s.token = ""
+	s.mu.Unlock()
+
+	return nil
+}
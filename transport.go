@@ -0,0 +1,355 @@
+package nopaper
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Defaults of http transport, they are the same as in http.DefaultTransport.
+const (
+	defaultMaxIdleConns        = 100
+	defaultMaxIdleConnsPerHost = 10
+	defaultIdleConnTimeout     = 90 * time.Second
+	defaultDialTimeout         = 30 * time.Second
+	defaultTLSHandshakeTimeout = 10 * time.Second
+)
+
+// newHTTPTransport builds http transport with TLS, proxy and connection pool settings from config.
+func newHTTPTransport(cfg Config) (*http.Transport, error) {
+	tlsCfg, err := newTLSConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	proxy := http.ProxyFromEnvironment
+	if cfg.ProxyURL != "" {
+		u, err := url.Parse(cfg.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy url: %w", err)
+		}
+
+		proxy = http.ProxyURL(u)
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   orDefault(cfg.DialTimeout, defaultDialTimeout),
+		KeepAlive: 30 * time.Second,
+	}
+
+	return &http.Transport{
+		Proxy:                 proxy,
+		DialContext:           dialer.DialContext,
+		TLSClientConfig:       tlsCfg,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          orDefault(cfg.MaxIdleConns, defaultMaxIdleConns),
+		MaxIdleConnsPerHost:   orDefault(cfg.MaxIdleConnsPerHost, defaultMaxIdleConnsPerHost),
+		MaxConnsPerHost:       cfg.MaxConnsPerHost,
+		IdleConnTimeout:       orDefault(cfg.IdleConnTimeout, defaultIdleConnTimeout),
+		TLSHandshakeTimeout:   orDefault(cfg.TLSHandshakeTimeout, defaultTLSHandshakeTimeout),
+		ResponseHeaderTimeout: cfg.ResponseHeaderTimeout,
+		ExpectContinueTimeout: time.Second,
+	}, nil
+}
+
+// newTLSConfig builds TLS config with additional CA certificates and client certificate.
+func newTLSConfig(cfg Config) (*tls.Config, error) {
+	minVersion, err := parseTLSVersion(cfg.MinTLSVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsCfg := &tls.Config{
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+		MinVersion:         minVersion,
+	}
+
+	if len(cfg.CAFiles) > 0 || cfg.CAPEM != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+
+		for _, path := range cfg.CAFiles {
+			bts, err := os.ReadFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("cant read ca file: %w", err)
+			}
+
+			if !pool.AppendCertsFromPEM(bts) {
+				return nil, fmt.Errorf("ca file %s contains no PEM certificates", path)
+			}
+		}
+
+		if cfg.CAPEM != "" && !pool.AppendCertsFromPEM([]byte(cfg.CAPEM)) {
+			return nil, fmt.Errorf("ca pem contains no certificates")
+		}
+
+		tlsCfg.RootCAs = pool
+	}
+
+	var cert tls.Certificate
+
+	switch {
+	case cfg.ClientCertFile != "":
+		cert, err = tls.LoadX509KeyPair(cfg.ClientCertFile, cfg.ClientKeyFile)
+	case cfg.ClientCertPEM != "":
+		cert, err = tls.X509KeyPair([]byte(cfg.ClientCertPEM), []byte(cfg.ClientKeyPEM))
+	default:
+		return tlsCfg, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("cant load client certificate: %w", err)
+	}
+
+	tlsCfg.Certificates = []tls.Certificate{cert}
+
+	return tlsCfg, nil
+}
+
+// parseTLSVersion converts version like "1.2" to tls package constant.
+// Empty version means TLS 1.2.
+func parseTLSVersion(v string) (uint16, error) {
+	switch v {
+	case "", "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, fmt.Errorf("invalid min_tls_version %q: must be 1.2 or 1.3", v)
+	}
+}
+
+// orDefault returns def for zero value.
+func orDefault[T comparable](v, def T) T {
+	var zero T
+	if v == zero {
+		return def
+	}
+
+	return v
+}
+
+// defaultRetryWait is a wait before first retry when Config.RetryWait is not set.
+const defaultRetryWait = 500 * time.Millisecond
+
+// retryTransport is transport that retries idempotent requests on network errors and temporary bad statuses.
+type retryTransport struct {
+	T        http.RoundTripper
+	retryMax int
+	wait     time.Duration
+}
+
+// newRetryTransport creates new retryTransport.
+func newRetryTransport(t http.RoundTripper, retryMax int, wait time.Duration) *retryTransport {
+	if wait <= 0 {
+		wait = defaultRetryWait
+	}
+
+	return &retryTransport{T: t, retryMax: retryMax, wait: wait}
+}
+
+// RoundTrip is default golang http tripper interface.
+func (rt *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !isIdempotent(req) {
+		return rt.T.RoundTrip(req)
+	}
+
+	wait := rt.wait
+
+	for attempt := 0; ; attempt++ {
+		resp, err := rt.T.RoundTrip(req)
+		if attempt >= rt.retryMax || !shouldRetry(resp, err) {
+			return resp, err
+		}
+
+		if resp != nil {
+			// Drain body to reuse connection.
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		t := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			t.Stop()
+
+			return nil, req.Context().Err()
+		case <-t.C:
+		}
+
+		wait *= 2
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+// noRetryKey is a context key of requests that must not be retried.
+type noRetryKey struct{}
+
+// withoutRetry marks context, so its requests are never retried, e.g. signing that must not happen twice.
+func withoutRetry(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noRetryKey{}, true)
+}
+
+// isIdempotent reports whether request can be safely sent again.
+func isIdempotent(req *http.Request) bool {
+	if noRetry, _ := req.Context().Value(noRetryKey{}).(bool); noRetry {
+		return false
+	}
+
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+	default:
+		return false
+	}
+
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// shouldRetry reports whether response or error is temporary.
+func shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+
+	return false
+}
+
+// rateLimitTransport is transport that waits for rate limiter before every request.
+type rateLimitTransport struct {
+	T       http.RoundTripper
+	limiter *rate.Limiter
+}
+
+// newRateLimitTransport creates new rateLimitTransport with limit in requests per second.
+func newRateLimitTransport(t http.RoundTripper, limit float64, burst int) *rateLimitTransport {
+	return &rateLimitTransport{T: t, limiter: rate.NewLimiter(rate.Limit(limit), orDefault(burst, 1))}
+}
+
+// RoundTrip is default golang http tripper interface.
+// The first request with context from withReservedRate does not wait, because caller already waited for it.
+func (rt *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if reserved, ok := req.Context().Value(rateReservedKey{}).(*atomic.Bool); ok && reserved.CompareAndSwap(true, false) {
+		return rt.T.RoundTrip(req)
+	}
+
+	if err := rt.limiter.Wait(req.Context()); err != nil {
+		return nil, err
+	}
+
+	return rt.T.RoundTrip(req)
+}
+
+// rateReservedKey is a context key of reservation made by Client.waitRate.
+type rateReservedKey struct{}
+
+// withReservedRate marks context, so its first request skips rate limiter wait.
+func withReservedRate(ctx context.Context) context.Context {
+	reserved := &atomic.Bool{}
+	reserved.Store(true)
+
+	return context.WithValue(ctx, rateReservedKey{}, reserved)
+}
+
+// RequestMetrics describes one request to Nopaper including its retries.
+type RequestMetrics struct {
+	// Labels are Config.MetricsLabels, e.g. tenant for ClientPool clients.
+	Labels map[string]string
+	Method string
+	// Path is a path relative to partner api with ids replaced by {id},
+	// e.g. /document/{id}/file, so it is safe as a metric label.
+	Path string
+	// StatusCode is zero on error.
+	StatusCode int
+	Duration   time.Duration
+	Err        error
+}
+
+// MetricsObserver receives metrics of requests to Nopaper, e.g. to export them to prometheus.
+type MetricsObserver interface {
+	ObserveRequest(m RequestMetrics)
+}
+
+// MetricsObserverFunc is a func based MetricsObserver.
+type MetricsObserverFunc func(m RequestMetrics)
+
+func (f MetricsObserverFunc) ObserveRequest(m RequestMetrics) {
+	f(m)
+}
+
+// metricsTransport is transport that reports every request to MetricsObserver.
+type metricsTransport struct {
+	T        http.RoundTripper
+	observer MetricsObserver
+	labels   map[string]string
+}
+
+// newMetricsTransport creates new metricsTransport.
+func newMetricsTransport(t http.RoundTripper, observer MetricsObserver, labels map[string]string) *metricsTransport {
+	return &metricsTransport{T: t, observer: observer, labels: labels}
+}
+
+// RoundTrip is default golang http tripper interface.
+func (mt *metricsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+
+	resp, err := mt.T.RoundTrip(req)
+
+	m := RequestMetrics{
+		Labels:   mt.labels,
+		Method:   req.Method,
+		Path:     metricsPath(req.URL.Path),
+		Duration: time.Since(start),
+		Err:      err,
+	}
+
+	if resp != nil {
+		m.StatusCode = resp.StatusCode
+	}
+
+	mt.observer.ObserveRequest(m)
+
+	return resp, err
+}
+
+// idPathSegment matches numeric and uuid path segments.
+var idPathSegment = regexp.MustCompile(`/([0-9]+|[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12})(/|$)`)
+
+// metricsPath cuts api prefix and replaces ids in path with {id}.
+func metricsPath(path string) string {
+	if i := strings.Index(path, apiPath); i >= 0 {
+		path = path[i+len(apiPath):]
+	}
+
+	// Replace twice because adjacent ids share slash between matches.
+	path = idPathSegment.ReplaceAllString(path, "/{id}$2")
+
+	return idPathSegment.ReplaceAllString(path, "/{id}$2")
+}
@@ -1,9 +1,11 @@
 package nopaper
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net/http"
+	"sync"
 )
 
 // emptyResponseResult is a helper for empty responses from nopaper.
@@ -20,27 +22,125 @@ func emptyResponseResult(r *http.Response) error {
 	}
 
 	return fmt.Errorf("unknown status code from nopaper: %s : %s", r.Status, string(bts))
-
 }
 
 // authHeaderTransport is transport that wraps old tripper with auth header add.
 type authHeaderTransport struct {
-	T     http.RoundTripper
-	token string
+	T      http.RoundTripper
+	tokens TokenSource
 }
 
 // RoundTrip is default golang http tripper interface.
+// Request rejected with 401 is sent once again if token source returns another token.
 func (adt *authHeaderTransport) RoundTrip(req *http.Request) (*http.Response, error) {
-	req.Header.Add("X-API-KEY", adt.token)
+	ctx := req.Context()
+
+	token, err := adt.tokens.Token(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("cant get nopaper token: %w", err)
+	}
+
+	resp, err := adt.T.RoundTrip(withToken(req, token))
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		// Body cant be sent again.
+		return resp, nil
+	}
+
+	if r, ok := adt.tokens.(TokenRefresher); ok {
+		if err := r.Refresh(ctx); err != nil {
+			return resp, nil
+		}
+	}
+
+	newToken, err := adt.tokens.Token(ctx)
+	if err != nil || newToken == token {
+		return resp, nil
+	}
+
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return resp, nil
+		}
+
+		req = req.Clone(ctx)
+		req.Body = body
+	}
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	return adt.T.RoundTrip(withToken(req, newToken))
+}
+
+// withToken returns copy of request with auth header.
+func withToken(req *http.Request, token string) *http.Request {
+	req = req.Clone(req.Context())
+	req.Header.Set("X-API-KEY", token)
 
-	return adt.T.RoundTrip(req)
+	return req
 }
 
-// newAuthHeaderTransport create new authHeaderTransport by token.
-func newAuthHeaderTransport(t http.RoundTripper, token string) *authHeaderTransport {
+// newAuthHeaderTransport create new authHeaderTransport by token source.
+func newAuthHeaderTransport(t http.RoundTripper, tokens TokenSource) *authHeaderTransport {
 	if t == nil {
 		t = http.DefaultTransport
 	}
 
-	return &authHeaderTransport{t, token}
+	return &authHeaderTransport{t, tokens}
+}
+
+// keyedMutex is a set of mutexes by key, e.g. to serialize work with one user phone.
+// Zero value is ready to use.
+type keyedMutex struct {
+	mu    sync.Mutex
+	locks map[string]*keyedLock
+}
+
+// keyedLock is a mutex as a channel with one slot, so waiting for it might be canceled.
+type keyedLock struct {
+	ch   chan struct{}
+	refs int
+}
+
+// Lock locks key and returns unlock func, it returns context error if context is done while waiting.
+func (m *keyedMutex) Lock(ctx context.Context, key string) (unlock func(), err error) {
+	m.mu.Lock()
+	if m.locks == nil {
+		m.locks = make(map[string]*keyedLock)
+	}
+
+	l, ok := m.locks[key]
+	if !ok {
+		l = &keyedLock{ch: make(chan struct{}, 1)}
+		m.locks[key] = l
+	}
+	l.refs++
+	m.mu.Unlock()
+
+	release := func() {
+		m.mu.Lock()
+		l.refs--
+		if l.refs == 0 {
+			delete(m.locks, key)
+		}
+		m.mu.Unlock()
+	}
+
+	select {
+	case l.ch <- struct{}{}:
+	case <-ctx.Done():
+		release()
+
+		return nil, ctx.Err()
+	}
+
+	return func() {
+		<-l.ch
+		release()
+	}, nil
 }
@@ -0,0 +1,94 @@
+package validation
+
+import (
+	"errors"
+	"strings"
+)
+
+type Error string
+
+func (e Error) String() string {
+	return string(e)
+}
+
+func (e Error) Error() string {
+	return e.String()
+}
+
+var (
+	ErrInvalidINN            Error = "invalid inn"
+	ErrInvalidKPP            Error = "invalid kpp"
+	ErrInvalidSNILS          Error = "invalid snils"
+	ErrInvalidPassportSeries Error = "invalid passport series"
+	ErrInvalidPassportNumber Error = "invalid passport number"
+	ErrInvalidDepartmentCode Error = "invalid passport issuer department code"
+	// ErrRequired - required field is empty.
+	ErrRequired Error = "field is required"
+)
+
+// FieldError is a validation error of one request field.
+type FieldError struct {
+	// Field is a json path of field, e.g. recipientInfoList[0].companyInn.
+	Field string
+	Err   error
+}
+
+func (e FieldError) Error() string {
+	return e.Field + ": " + e.Err.Error()
+}
+
+func (e FieldError) Unwrap() error {
+	return e.Err
+}
+
+// Errors is a list of field errors of request, errors.Is and errors.As work with every item.
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, fe := range e {
+		msgs = append(msgs, fe.Error())
+	}
+
+	return "validation failed: " + strings.Join(msgs, "; ")
+}
+
+func (e Errors) Unwrap() []error {
+	errs := make([]error, 0, len(e))
+	for _, fe := range e {
+		errs = append(errs, fe)
+	}
+
+	return errs
+}
+
+// Add appends error of field if it is not nil.
+func (e *Errors) Add(field string, err error) {
+	if err != nil {
+		*e = append(*e, FieldError{Field: field, Err: err})
+	}
+}
+
+// Err returns nil for empty list, so it might be returned as error directly.
+func (e Errors) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+
+	return e
+}
+
+// Fields returns field errors from err if it contains Errors or FieldError.
+func Fields(err error) []FieldError {
+	var errs Errors
+	if errors.As(err, &errs) {
+		return errs
+	}
+
+	var fe FieldError
+	if errors.As(err, &fe) {
+		return []FieldError{fe}
+	}
+
+	return nil
+}
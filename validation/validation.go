@@ -0,0 +1,146 @@
+// Package validation checks russian identifiers before they are sent to Nopaper:
+// INN, KPP, SNILS and passport data.
+package validation
+
+import (
+	"fmt"
+	"strings"
+)
+
+// INN checks taxpayer number: 10 digits for legal entities and 12 digits for individuals,
+// control digits are verified.
+func INN(inn string) error {
+	if !isDigits(inn) {
+		return fmt.Errorf("%w: must contain only digits", ErrInvalidINN)
+	}
+
+	d := digits(inn)
+
+	switch len(d) {
+	case 10:
+		if innChecksum(d[:9], []int{2, 4, 10, 3, 5, 9, 4, 6, 8}) != d[9] {
+			return fmt.Errorf("%w: checksum mismatch", ErrInvalidINN)
+		}
+	case 12:
+		if innChecksum(d[:10], []int{7, 2, 4, 10, 3, 5, 9, 4, 6, 8}) != d[10] ||
+			innChecksum(d[:11], []int{3, 7, 2, 4, 10, 3, 5, 9, 4, 6, 8}) != d[11] {
+			return fmt.Errorf("%w: checksum mismatch", ErrInvalidINN)
+		}
+	default:
+		return fmt.Errorf("%w: must contain 10 or 12 digits", ErrInvalidINN)
+	}
+
+	return nil
+}
+
+// IsLegalEntityINN reports whether INN belongs to legal entity, i.e. contains 10 digits.
+func IsLegalEntityINN(inn string) bool {
+	return len(inn) == 10
+}
+
+// KPP checks tax registration reason code: 4 digits of tax office,
+// 2 digits or capital latin letters of reason and 3 digits of number.
+func KPP(kpp string) error {
+	if len(kpp) != 9 {
+		return fmt.Errorf("%w: must contain 9 characters", ErrInvalidKPP)
+	}
+
+	if !isDigits(kpp[:4]) || !isDigits(kpp[6:]) {
+		return fmt.Errorf("%w: invalid format", ErrInvalidKPP)
+	}
+
+	for _, r := range kpp[4:6] {
+		if !(r >= '0' && r <= '9') && !(r >= 'A' && r <= 'Z') {
+			return fmt.Errorf("%w: invalid reason code", ErrInvalidKPP)
+		}
+	}
+
+	return nil
+}
+
+// SNILS checks insurance number with control digits.
+// Both 12345678901 and 123-456-789 01 forms are accepted.
+func SNILS(snils string) error {
+	s := strings.NewReplacer("-", "", " ", "").Replace(snils)
+	if len(s) != 11 || !isDigits(s) {
+		return fmt.Errorf("%w: must contain 11 digits", ErrInvalidSNILS)
+	}
+
+	d := digits(s)
+
+	sum := 0
+	for i := 0; i < 9; i++ {
+		sum += d[i] * (9 - i)
+	}
+
+	control := sum % 101
+	if control == 100 {
+		control = 0
+	}
+
+	if control != d[9]*10+d[10] {
+		return fmt.Errorf("%w: checksum mismatch", ErrInvalidSNILS)
+	}
+
+	return nil
+}
+
+// PassportSeries checks series of russian passport: 4 digits.
+func PassportSeries(series string) error {
+	if len(series) != 4 || !isDigits(series) {
+		return fmt.Errorf("%w: must contain 4 digits", ErrInvalidPassportSeries)
+	}
+
+	return nil
+}
+
+// PassportNumber checks number of russian passport: 6 digits.
+func PassportNumber(number string) error {
+	if len(number) != 6 || !isDigits(number) {
+		return fmt.Errorf("%w: must contain 6 digits", ErrInvalidPassportNumber)
+	}
+
+	return nil
+}
+
+// DepartmentCode checks code of passport issuer department in XXX-XXX form.
+func DepartmentCode(code string) error {
+	if len(code) != 7 || code[3] != '-' || !isDigits(code[:3]) || !isDigits(code[4:]) {
+		return fmt.Errorf("%w: must be in XXX-XXX form", ErrInvalidDepartmentCode)
+	}
+
+	return nil
+}
+
+// innChecksum calculates INN control digit by coefficients.
+func innChecksum(d []int, coefficients []int) int {
+	sum := 0
+	for i, c := range coefficients {
+		sum += d[i] * c
+	}
+
+	return sum % 11 % 10
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+func digits(s string) []int {
+	d := make([]int, len(s))
+	for i := range s {
+		d[i] = int(s[i] - '0')
+	}
+
+	return d
+}
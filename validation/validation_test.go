@@ -0,0 +1,78 @@
+package validation
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidators(t *testing.T) {
+	tests := []struct {
+		name  string
+		check func(string) error
+		in    string
+		err   error
+	}{
+		{"inn legal entity", INN, "7707083893", nil},
+		{"inn individual", INN, "500100732259", nil},
+		{"inn legal entity checksum", INN, "7707083894", ErrInvalidINN},
+		{"inn individual first checksum", INN, "500100732269", ErrInvalidINN},
+		{"inn individual second checksum", INN, "500100732258", ErrInvalidINN},
+		{"inn length", INN, "77070838", ErrInvalidINN},
+		{"inn letters", INN, "77070838a3", ErrInvalidINN},
+		{"inn empty", INN, "", ErrInvalidINN},
+
+		{"kpp digits", KPP, "773601001", nil},
+		{"kpp reason letters", KPP, "7736AB001", nil},
+		{"kpp lower letters", KPP, "7736ab001", ErrInvalidKPP},
+		{"kpp length", KPP, "77360100", ErrInvalidKPP},
+		{"kpp letters in office", KPP, "77A601001", ErrInvalidKPP},
+
+		{"snils", SNILS, "11223344595", nil},
+		{"snils formatted", SNILS, "112-233-445 95", nil},
+		{"snils checksum", SNILS, "11223344596", ErrInvalidSNILS},
+		{"snils length", SNILS, "1122334459", ErrInvalidSNILS},
+		{"snils letters", SNILS, "1122334459a", ErrInvalidSNILS},
+
+		{"passport series", PassportSeries, "4510", nil},
+		{"passport series length", PassportSeries, "451", ErrInvalidPassportSeries},
+		{"passport series letters", PassportSeries, "45a0", ErrInvalidPassportSeries},
+
+		{"passport number", PassportNumber, "123456", nil},
+		{"passport number length", PassportNumber, "1234567", ErrInvalidPassportNumber},
+
+		{"department code", DepartmentCode, "770-001", nil},
+		{"department code without dash", DepartmentCode, "770001", ErrInvalidDepartmentCode},
+		{"department code letters", DepartmentCode, "77a-001", ErrInvalidDepartmentCode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.check(tt.in)
+			if !errors.Is(err, tt.err) || (tt.err == nil) != (err == nil) {
+				t.Errorf("check(%q) = %v, want %v", tt.in, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestErrors(t *testing.T) {
+	var errs Errors
+
+	errs.Add("title", nil)
+	if errs.Err() != nil {
+		t.Fatalf("Err() of empty list = %v", errs.Err())
+	}
+
+	errs.Add("companyInn", INN("1"))
+	errs.Add("companyKpp", ErrRequired)
+
+	err := errs.Err()
+	if !errors.Is(err, ErrInvalidINN) || !errors.Is(err, ErrRequired) {
+		t.Errorf("errors.Is does not match field errors: %v", err)
+	}
+
+	fields := Fields(err)
+	if len(fields) != 2 || fields[0].Field != "companyInn" || fields[1].Field != "companyKpp" {
+		t.Errorf("Fields() = %v", fields)
+	}
+}
@@ -0,0 +1,191 @@
+package verify
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"fmt"
+)
+
+var (
+	oidSignedData    = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 2}
+	oidMessageDigest = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 4}
+
+	oidKeyRSA     = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 1}
+	oidKeyECDSA   = asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}
+	oidKeyEd25519 = asn1.ObjectIdentifier{1, 3, 101, 112}
+)
+
+// digestAlgorithms are supported CMS digest algorithms.
+var digestAlgorithms = []struct {
+	oid  asn1.ObjectIdentifier
+	hash crypto.Hash
+}{
+	{asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1}, crypto.SHA256},
+	{asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 2}, crypto.SHA384},
+	{asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 3}, crypto.SHA512},
+}
+
+// contentInfo, signedData, encapContentInfo, signerInfo and attribute are CMS structures of RFC 5652.
+type contentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue `asn1:"explicit,tag:0"`
+}
+
+type signedData struct {
+	Version          int
+	DigestAlgorithms []pkix.AlgorithmIdentifier `asn1:"set"`
+	EncapContentInfo encapContentInfo
+	Certificates     asn1.RawValue `asn1:"optional,tag:0"`
+	CRLs             asn1.RawValue `asn1:"optional,tag:1"`
+	SignerInfos      []signerInfo  `asn1:"set"`
+}
+
+type encapContentInfo struct {
+	EContentType asn1.ObjectIdentifier
+	EContent     []byte `asn1:"optional,explicit,tag:0"`
+}
+
+type signerInfo struct {
+	Version            int
+	SID                asn1.RawValue
+	DigestAlgorithm    pkix.AlgorithmIdentifier
+	SignedAttrs        asn1.RawValue `asn1:"optional,tag:0"`
+	SignatureAlgorithm pkix.AlgorithmIdentifier
+	Signature          []byte
+	UnsignedAttrs      asn1.RawValue `asn1:"optional,tag:1"`
+}
+
+type attribute struct {
+	Type   asn1.ObjectIdentifier
+	Values asn1.RawValue
+}
+
+// IsCMS reports whether signature is DER encoded CMS(PKCS#7) SignedData.
+func IsCMS(signature []byte) bool {
+	var ci contentInfo
+
+	_, err := asn1.Unmarshal(signature, &ci)
+
+	return err == nil && ci.ContentType.Equal(oidSignedData)
+}
+
+// CMS verifies DER encoded CMS(PKCS#7) SignedData signature of data by public key.
+// Data might be nil for attached signature, then encapsulated content is verified.
+// Signature is valid if any signer signed data with the key, with or without signed attributes.
+func CMS(pub crypto.PublicKey, data, signature []byte) error {
+	var ci contentInfo
+
+	if rest, err := asn1.Unmarshal(signature, &ci); err != nil || len(rest) > 0 {
+		return fmt.Errorf("%w: not a DER encoded CMS", ErrInvalidSignature)
+	}
+
+	if !ci.ContentType.Equal(oidSignedData) {
+		return fmt.Errorf("%w: CMS content type %s is not signed data", ErrInvalidSignature, ci.ContentType)
+	}
+
+	var sd signedData
+
+	if _, err := asn1.Unmarshal(ci.Content.Bytes, &sd); err != nil {
+		return fmt.Errorf("%w: cant parse CMS signed data: %v", ErrInvalidSignature, err)
+	}
+
+	if data == nil {
+		data = sd.EncapContentInfo.EContent
+	}
+
+	if len(sd.SignerInfos) == 0 {
+		return fmt.Errorf("%w: CMS has no signers", ErrInvalidSignature)
+	}
+
+	var err error
+
+	for _, si := range sd.SignerInfos {
+		if err = verifySigner(pub, data, si); err == nil {
+			return nil
+		}
+	}
+
+	return err
+}
+
+// verifySigner verifies signature of one CMS signer.
+func verifySigner(pub crypto.PublicKey, data []byte, si signerInfo) error {
+	hash, ok := digestHash(si.DigestAlgorithm.Algorithm)
+	if !ok {
+		return fmt.Errorf("%w: CMS digest algorithm %s", ErrUnsupportedKey, si.DigestAlgorithm.Algorithm)
+	}
+
+	if len(si.SignedAttrs.FullBytes) == 0 {
+		return verifyRaw(pub, hash, data, si.Signature)
+	}
+
+	// Signed attributes are signed in DER form with SET tag instead of implicit [0].
+	signed := bytes.Clone(si.SignedAttrs.FullBytes)
+	signed[0] = 0x31
+
+	var attrs []attribute
+
+	if _, err := asn1.UnmarshalWithParams(signed, &attrs, "set"); err != nil {
+		return fmt.Errorf("%w: cant parse CMS signed attributes: %v", ErrInvalidSignature, err)
+	}
+
+	h := hash.New()
+	h.Write(data)
+
+	if !hasMessageDigest(attrs, h.Sum(nil)) {
+		return fmt.Errorf("%w: CMS message digest does not match data", ErrInvalidSignature)
+	}
+
+	return verifyRaw(pub, hash, signed, si.Signature)
+}
+
+// hasMessageDigest reports whether attributes contain message digest equal to digest.
+func hasMessageDigest(attrs []attribute, digest []byte) bool {
+	for _, a := range attrs {
+		if !a.Type.Equal(oidMessageDigest) {
+			continue
+		}
+
+		var value []byte
+
+		if _, err := asn1.Unmarshal(a.Values.Bytes, &value); err != nil {
+			return false
+		}
+
+		return bytes.Equal(value, digest)
+	}
+
+	return false
+}
+
+func digestHash(oid asn1.ObjectIdentifier) (crypto.Hash, bool) {
+	for _, d := range digestAlgorithms {
+		if d.oid.Equal(oid) {
+			return d.hash, true
+		}
+	}
+
+	return 0, false
+}
+
+// publicKeyAlgorithm returns algorithm of PKIX public key structure.
+func publicKeyAlgorithm(der []byte) (asn1.ObjectIdentifier, bool) {
+	var spki struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}
+
+	rest, err := asn1.Unmarshal(der, &spki)
+	if err != nil || len(rest) > 0 {
+		return nil, false
+	}
+
+	return spki.Algorithm.Algorithm, true
+}
+
+// knownKeyAlgorithm reports whether key algorithm is supported, so parse errors mean invalid key.
+func knownKeyAlgorithm(oid asn1.ObjectIdentifier) bool {
+	return oid.Equal(oidKeyRSA) || oid.Equal(oidKeyECDSA) || oid.Equal(oidKeyEd25519)
+}
@@ -0,0 +1,195 @@
+package verify
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Fixtures in testdata are made by openssl:
+//
+//	openssl cms -sign -binary -in act.txt -signer rsa.crt -inkey rsa.key -md sha256 -outform DER -out rsa.p7s
+//	openssl cms -sign -binary -noattr -in act.txt -signer rsa.crt -inkey rsa.key -md sha256 -outform DER -out rsa_noattr.p7s
+//	openssl cms -sign -binary -in act.txt -signer ec.crt -inkey ec.key -md sha384 -outform DER -out ec.p7s
+
+var oidSigningTime = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 5}
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+
+	bts, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return bts
+}
+
+func loadCertificate(path string) (*x509.Certificate, error) {
+	bts, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(bts)
+	if block == nil {
+		return nil, fmt.Errorf("no pem in %s", path)
+	}
+
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// replaceOID replaces every DER encoded object identifier in der, both must have the same length.
+func replaceOID(t *testing.T, der []byte, old, new asn1.ObjectIdentifier) []byte {
+	t.Helper()
+
+	oldDER, err := asn1.Marshal(old)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newDER, err := asn1.Marshal(new)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(oldDER) != len(newDER) || !bytes.Contains(der, oldDER) {
+		t.Fatalf("cant replace %s with %s", old, new)
+	}
+
+	return bytes.ReplaceAll(der, oldDER, newDER)
+}
+
+// tamperAttribute changes the last byte of signed attribute value, DER structure stays valid.
+func tamperAttribute(t *testing.T, sig []byte, oid asn1.ObjectIdentifier) []byte {
+	t.Helper()
+
+	oidDER, err := asn1.Marshal(oid)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	i := bytes.Index(sig, oidDER)
+	if i < 0 {
+		t.Fatalf("attribute %s is not found", oid)
+	}
+
+	// Attribute is SEQUENCE { oid, SET { value } }, value is the rest of the sequence.
+	var attr attribute
+	if _, err := asn1.Unmarshal(sig[i-2:], &attr); err != nil {
+		t.Fatal(err)
+	}
+
+	end := i + len(oidDER) + len(attr.Values.FullBytes)
+
+	tampered := bytes.Clone(sig)
+	tampered[end-2]++
+
+	return tampered
+}
+
+func TestCMS(t *testing.T) {
+	act := readFixture(t, "act.txt")
+
+	tests := []struct {
+		sig  string
+		cert string
+	}{
+		{"rsa.p7s", "rsa.crt"},
+		{"rsa_noattr.p7s", "rsa.crt"},
+		{"ec.p7s", "ec.crt"},
+	}
+
+	for _, tt := range tests {
+		cert, err := loadCertificate(filepath.Join("testdata", tt.cert))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		other := "ec.crt"
+		if tt.cert == other {
+			other = "rsa.crt"
+		}
+
+		otherCert, err := loadCertificate(filepath.Join("testdata", other))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		sig := readFixture(t, tt.sig)
+
+		if !IsCMS(sig) {
+			t.Errorf("%s: IsCMS() = false", tt.sig)
+		}
+
+		if err := Signature(cert.PublicKey, act, sig); err != nil {
+			t.Errorf("%s: valid signature error = %v", tt.sig, err)
+		}
+
+		invalid := []struct {
+			name string
+			pub  any
+			data []byte
+			sig  []byte
+		}{
+			{name: "tampered content", pub: cert.PublicKey, data: append(bytes.Clone(act), '.'), sig: sig},
+			{name: "other key", pub: otherCert.PublicKey, data: act, sig: sig},
+			{name: "empty content", pub: cert.PublicKey, data: []byte{}, sig: sig},
+		}
+
+		if tt.sig != "rsa_noattr.p7s" {
+			invalid = append(invalid,
+				struct {
+					name string
+					pub  any
+					data []byte
+					sig  []byte
+				}{name: "tampered signing time", pub: cert.PublicKey, data: act, sig: tamperAttribute(t, sig, oidSigningTime)},
+				struct {
+					name string
+					pub  any
+					data []byte
+					sig  []byte
+				}{name: "tampered message digest", pub: cert.PublicKey, data: act, sig: tamperAttribute(t, sig, oidMessageDigest)},
+			)
+		}
+
+		for _, inv := range invalid {
+			if err := Signature(inv.pub, inv.data, inv.sig); !errors.Is(err, ErrInvalidSignature) {
+				t.Errorf("%s: %s error = %v, want %v", tt.sig, inv.name, err, ErrInvalidSignature)
+			}
+		}
+	}
+}
+
+func TestCMSMalformed(t *testing.T) {
+	cert, err := loadCertificate("testdata/rsa.crt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sig := readFixture(t, "rsa_noattr.p7s")
+	act := readFixture(t, "act.txt")
+
+	tests := []struct {
+		name string
+		sig  []byte
+		err  error
+	}{
+		{name: "trailing data", sig: append(bytes.Clone(sig), 0), err: ErrInvalidSignature},
+		{name: "truncated", sig: sig[:len(sig)-10], err: ErrInvalidSignature},
+		{name: "unsupported digest", sig: replaceOID(t, sig, digestAlgorithms[0].oid, []int{2, 16, 840, 1, 101, 3, 4, 2, 8}), err: ErrUnsupportedKey},
+	}
+
+	for _, tt := range tests {
+		if err := CMS(cert.PublicKey, act, tt.sig); !errors.Is(err, tt.err) {
+			t.Errorf("%s: CMS() error = %v, want %v", tt.name, err, tt.err)
+		}
+	}
+}
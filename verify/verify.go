@@ -0,0 +1,307 @@
+// Package verify checks detached signatures of files signed in Nopaper offline,
+// using public key of signer certificate from nopaper.CertificateInfo.
+//
+// Nopaper does not document its signature format, so both detached CMS(PKCS#7) SignedData
+// and raw signatures over SHA-256 of file are accepted. Signature covers the original file
+// (GetFileIDsInDocument OriginFileList), not the stamped copy, so the original one must be verified.
+//
+// ECDSA, RSA and Ed25519 keys are supported. GOST keys are not supported by Go standard library
+// and are reported with ErrUnsupportedKey.
+package verify
+
+import (
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/google/uuid"
+
+	nopaper "github.com/KaymeKaydex/go-nopaper-client"
+)
+
+type Error string
+
+func (e Error) String() string {
+	return string(e)
+}
+
+func (e Error) Error() string {
+	return e.String()
+}
+
+var (
+	ErrInvalidPublicKey Error = "invalid public key"
+	ErrUnsupportedKey   Error = "unsupported public key algorithm"
+	ErrInvalidSignature Error = "signature is not valid"
+)
+
+// ParsePublicKey parses public key from CertificateCustomData.PublicKey.
+// Key might be PEM encoded public key or certificate, base64 or hex encoded DER,
+// or base64 or hex encoded uncompressed P-256 point.
+// Text that is both valid base64 and hex is parsed in the encoding that gives a valid key.
+func ParsePublicKey(s string) (crypto.PublicKey, error) {
+	candidates, err := decodeKey(s)
+	if err != nil {
+		return nil, err
+	}
+
+	var firstErr error
+
+	for _, der := range candidates {
+		pub, err := parseDER(der)
+		if err == nil {
+			return pub, nil
+		}
+
+		if firstErr == nil || errors.Is(err, ErrUnsupportedKey) {
+			firstErr = err
+		}
+	}
+
+	return nil, firstErr
+}
+
+// KeyFingerprint returns Fingerprint of public key from CertificateCustomData.PublicKey.
+// Keys that cant be parsed, e.g. GOST ones, are fingerprinted by SHA-256 of their decoded bytes,
+// which is the same value for keys in PKIX DER form; base64 is preferred for text that is valid hex too.
+func KeyFingerprint(s string) (string, error) {
+	if pub, err := ParsePublicKey(s); err == nil {
+		return Fingerprint(pub)
+	}
+
+	candidates, err := decodeKey(s)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(candidates[0])
+
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// decodeKey returns bytes of PEM block or all decodings of base64 or hex key, base64 goes first.
+func decodeKey(s string) ([][]byte, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, fmt.Errorf("%w: key is empty", ErrInvalidPublicKey)
+	}
+
+	if block, _ := pem.Decode([]byte(s)); block != nil {
+		return [][]byte{block.Bytes}, nil
+	}
+
+	var candidates [][]byte
+
+	for _, enc := range []Encoding{Base64, Hex} {
+		if der, err := decode(s, enc); err == nil {
+			candidates = append(candidates, der)
+		}
+	}
+
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("%w: value is neither base64 nor hex", ErrInvalidPublicKey)
+	}
+
+	return candidates, nil
+}
+
+// parseDER parses PKIX public key, certificate or raw P-256 point.
+func parseDER(der []byte) (crypto.PublicKey, error) {
+	if pub, err := x509.ParsePKIXPublicKey(der); err == nil {
+		return pub, nil
+	} else if oid, ok := publicKeyAlgorithm(der); ok && !knownKeyAlgorithm(oid) {
+		return nil, fmt.Errorf("%w: algorithm %s", ErrUnsupportedKey, oid)
+	}
+
+	if cert, err := x509.ParseCertificate(der); err == nil {
+		if cert.PublicKeyAlgorithm == x509.UnknownPublicKeyAlgorithm {
+			return nil, ErrUnsupportedKey
+		}
+
+		return cert.PublicKey, nil
+	}
+
+	if len(der) == 65 && der[0] == 4 {
+		if _, err := ecdh.P256().NewPublicKey(der); err == nil {
+			return &ecdsa.PublicKey{
+				Curve: elliptic.P256(),
+				X:     new(big.Int).SetBytes(der[1:33]),
+				Y:     new(big.Int).SetBytes(der[33:]),
+			}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: unknown key format", ErrInvalidPublicKey)
+}
+
+// Fingerprint returns hex encoded SHA-256 of PKIX DER form of public key.
+func Fingerprint(pub crypto.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(der)
+
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Encoding is a text encoding of signature.
+type Encoding int
+
+const (
+	// Base64 is standard or url base64 with or without padding.
+	Base64 Encoding = iota + 1
+	// Hex is hex encoding in any case.
+	Hex
+)
+
+// DecodeSignature decodes detached signature in encoding, whitespaces are ignored.
+// Encoding is not guessed, since hex text is valid base64 too.
+func DecodeSignature(s string, enc Encoding) ([]byte, error) {
+	bts, err := decode(s, enc)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+
+	return bts, nil
+}
+
+// Signature verifies detached signature of data made with SHA-256.
+// Signature might be CMS(PKCS#7) SignedData, see CMS, or raw signature;
+// raw ECDSA signature might be ASN.1 or r||s encoded.
+func Signature(pub crypto.PublicKey, data, signature []byte) error {
+	if IsCMS(signature) {
+		return CMS(pub, data, signature)
+	}
+
+	return verifyRaw(pub, crypto.SHA256, data, signature)
+}
+
+// verifyRaw verifies signature of message hashed with hash, Ed25519 signs message itself.
+func verifyRaw(pub crypto.PublicKey, hash crypto.Hash, message, signature []byte) error {
+	h := hash.New()
+	h.Write(message)
+	digest := h.Sum(nil)
+
+	switch key := pub.(type) {
+	case *ecdsa.PublicKey:
+		if ecdsa.VerifyASN1(key, digest, signature) {
+			return nil
+		}
+
+		size := (key.Curve.Params().BitSize + 7) / 8
+		if len(signature) == 2*size {
+			r := new(big.Int).SetBytes(signature[:size])
+			s := new(big.Int).SetBytes(signature[size:])
+
+			if ecdsa.Verify(key, digest, r, s) {
+				return nil
+			}
+		}
+	case *rsa.PublicKey:
+		if rsa.VerifyPKCS1v15(key, hash, digest, signature) == nil ||
+			rsa.VerifyPSS(key, hash, digest, signature, nil) == nil {
+			return nil
+		}
+	case ed25519.PublicKey:
+		if ed25519.Verify(key, message, signature) {
+			return nil
+		}
+	default:
+		return fmt.Errorf("%w: %T", ErrUnsupportedKey, pub)
+	}
+
+	return ErrInvalidSignature
+}
+
+// Result is a result of successful file verification.
+type Result struct {
+	FileName      string
+	CertificateID uuid.UUID
+	// OwnerID and OwnerName identify signer, they are taken from certificate.
+	OwnerID   uuid.UUID
+	OwnerName string
+	// Fingerprint is a fingerprint of signer public key.
+	Fingerprint string
+	// CertificateStatus is a status of certificate when it was fetched, signature is valid regardless of it.
+	CertificateStatus nopaper.CertificateStatus
+}
+
+// File verifies that original file from Client.GetFilesByID is signed by certificate owner.
+// Stamped copies (OriginFileWithStampList) differ from signed content and never pass verification:
+// Nopaper does not document how stamp is applied, so signed content cant be restored from them.
+// Download original file from OriginFileList instead.
+func File(cert nopaper.CertificateInfo, file nopaper.FileInfoResponse, signature []byte) (*Result, error) {
+	data, err := base64.StdEncoding.DecodeString(file.FileBase64)
+	if err != nil {
+		return nil, fmt.Errorf("cant decode file %s: %w", file.FileNameWithExtension, err)
+	}
+
+	return Data(cert, file.FileNameWithExtension, data, signature)
+}
+
+// Data verifies that data is signed by certificate owner, name is used only in result.
+func Data(cert nopaper.CertificateInfo, name string, data, signature []byte) (*Result, error) {
+	pub, err := ParsePublicKey(cert.CustomData.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("certificate %s: %w", cert.ID, err)
+	}
+
+	if err := Signature(pub, data, signature); err != nil {
+		return nil, fmt.Errorf("file %s, certificate %s: %w", name, cert.ID, err)
+	}
+
+	fingerprint, err := Fingerprint(pub)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Result{
+		FileName:          name,
+		CertificateID:     cert.ID,
+		OwnerID:           cert.OwnerID,
+		OwnerName:         cert.OwnerName,
+		Fingerprint:       fingerprint,
+		CertificateStatus: cert.Status,
+	}, nil
+}
+
+// decode decodes text in encoding, whitespaces are ignored.
+func decode(s string, enc Encoding) ([]byte, error) {
+	s = strings.Join(strings.Fields(s), "")
+
+	switch enc {
+	case Base64:
+		for _, b64 := range []*base64.Encoding{
+			base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding,
+		} {
+			if bts, err := b64.DecodeString(s); err == nil {
+				return bts, nil
+			}
+		}
+
+		return nil, fmt.Errorf("value is not base64")
+	case Hex:
+		bts, err := hex.DecodeString(s)
+		if err != nil {
+			return nil, fmt.Errorf("value is not hex: %w", err)
+		}
+
+		return bts, nil
+	default:
+		return nil, fmt.Errorf("unknown encoding %d", enc)
+	}
+}
@@ -0,0 +1,259 @@
+package verify
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	nopaper "github.com/KaymeKaydex/go-nopaper-client"
+)
+
+var data = []byte("Акт приёмки №1")
+
+// signer is a generated key with its raw signature of data.
+type signer struct {
+	name string
+	pub  crypto.PublicKey
+	sign func(t *testing.T, data []byte) []byte
+}
+
+func signers(t *testing.T) []signer {
+	t.Helper()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	edPub, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	digest := func(data []byte) []byte {
+		sum := sha256.Sum256(data)
+
+		return sum[:]
+	}
+
+	must := func(sig []byte, err error) []byte {
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return sig
+	}
+
+	return []signer{
+		{"rsa pkcs1", &rsaKey.PublicKey, func(_ *testing.T, data []byte) []byte {
+			return must(rsa.SignPKCS1v15(rand.Reader, rsaKey, crypto.SHA256, digest(data)))
+		}},
+		{"rsa pss", &rsaKey.PublicKey, func(_ *testing.T, data []byte) []byte {
+			return must(rsa.SignPSS(rand.Reader, rsaKey, crypto.SHA256, digest(data), nil))
+		}},
+		{"ecdsa asn1", &ecKey.PublicKey, func(_ *testing.T, data []byte) []byte {
+			return must(ecdsa.SignASN1(rand.Reader, ecKey, digest(data)))
+		}},
+		{"ecdsa r||s", &ecKey.PublicKey, func(t *testing.T, data []byte) []byte {
+			r, s, err := ecdsa.Sign(rand.Reader, ecKey, digest(data))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			sig := make([]byte, 64)
+			r.FillBytes(sig[:32])
+			s.FillBytes(sig[32:])
+
+			return sig
+		}},
+		{"ed25519", edPub, func(_ *testing.T, data []byte) []byte {
+			return ed25519.Sign(edKey, data)
+		}},
+	}
+}
+
+func TestSignature(t *testing.T) {
+	other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, s := range signers(t) {
+		sig := s.sign(t, data)
+
+		if err := Signature(s.pub, data, sig); err != nil {
+			t.Errorf("%s: valid signature error = %v", s.name, err)
+		}
+
+		tampered := append([]byte("X"), data[1:]...)
+		if err := Signature(s.pub, tampered, sig); !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("%s: tampered content error = %v, want %v", s.name, err, ErrInvalidSignature)
+		}
+
+		broken := append([]byte(nil), sig...)
+		broken[len(broken)/2] ^= 0xff
+
+		if err := Signature(s.pub, data, broken); !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("%s: tampered signature error = %v, want %v", s.name, err, ErrInvalidSignature)
+		}
+
+		if _, ok := s.pub.(*ecdsa.PublicKey); !ok {
+			continue
+		}
+
+		if err := Signature(&other.PublicKey, data, sig); !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("%s: other key error = %v, want %v", s.name, err, ErrInvalidSignature)
+		}
+	}
+}
+
+func TestParsePublicKey(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	point, err := key.PublicKey.ECDH()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert, err := loadCertificate("testdata/ec.crt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// GOST R 34.10-2012 256 bit key.
+	gost, err := asn1.Marshal(struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}{
+		Algorithm: pkix.AlgorithmIdentifier{Algorithm: asn1.ObjectIdentifier{1, 2, 643, 7, 1, 1, 1, 1}},
+		PublicKey: asn1.BitString{Bytes: make([]byte, 67), BitLength: 67 * 8},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		key  string
+		want crypto.PublicKey
+		err  error
+	}{
+		{name: "pem", key: string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})), want: &key.PublicKey},
+		{name: "base64 der", key: base64.StdEncoding.EncodeToString(der), want: &key.PublicKey},
+		{name: "hex der", key: hex.EncodeToString(der), want: &key.PublicKey},
+		{name: "base64 point", key: base64.RawURLEncoding.EncodeToString(point.Bytes()), want: &key.PublicKey},
+		{name: "hex point", key: hex.EncodeToString(point.Bytes()), want: &key.PublicKey},
+		{name: "certificate", key: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})), want: cert.PublicKey},
+		{name: "empty", err: ErrInvalidPublicKey},
+		{name: "garbage", key: "not a key!", err: ErrInvalidPublicKey},
+		{name: "truncated", key: base64.StdEncoding.EncodeToString(der[:40]), err: ErrInvalidPublicKey},
+		{name: "gost", key: base64.StdEncoding.EncodeToString(gost), err: ErrUnsupportedKey},
+	}
+
+	for _, tt := range tests {
+		pub, err := ParsePublicKey(tt.key)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("%s: ParsePublicKey() error = %v, want %v", tt.name, err, tt.err)
+
+			continue
+		}
+
+		if tt.want != nil && !tt.want.(interface{ Equal(crypto.PublicKey) bool }).Equal(pub) {
+			t.Errorf("%s: ParsePublicKey() = %v, want %v", tt.name, pub, tt.want)
+		}
+	}
+
+	fingerprint, err := KeyFingerprint(base64.StdEncoding.EncodeToString(gost))
+	sum := sha256.Sum256(gost)
+
+	if err != nil || fingerprint != hex.EncodeToString(sum[:]) {
+		t.Errorf("KeyFingerprint() of unsupported key = %s, %v, want sha256 of der", fingerprint, err)
+	}
+}
+
+func TestDecodeSignature(t *testing.T) {
+	// Signature bytes whose base64 form consists of hex digits only.
+	sig, err := base64.StdEncoding.DecodeString("0123456789abcdef")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		text string
+		enc  Encoding
+		want []byte
+		ok   bool
+	}{
+		{text: "0123456789abcdef", enc: Base64, want: sig, ok: true},
+		{text: "0123 4567\n89ab cdef", enc: Base64, want: sig, ok: true},
+		{text: "0123456789abcdef", enc: Hex, want: []byte{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef}, ok: true},
+		{text: base64.RawURLEncoding.EncodeToString([]byte{0xfb, 0xff}), enc: Base64, want: []byte{0xfb, 0xff}, ok: true},
+		{text: "zz", enc: Hex},
+		{text: "!!", enc: Base64},
+		{text: "00", enc: 0},
+	}
+
+	for _, tt := range tests {
+		got, err := DecodeSignature(tt.text, tt.enc)
+		if (err == nil) != tt.ok || string(got) != string(tt.want) {
+			t.Errorf("DecodeSignature(%q, %d) = %x, %v, want %x", tt.text, tt.enc, got, err, tt.want)
+		}
+	}
+}
+
+func TestData(t *testing.T) {
+	cert, err := loadCertificate("testdata/ec.crt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info := nopaper.CertificateInfo{
+		ID:         uuid.New(),
+		OwnerID:    uuid.New(),
+		OwnerName:  "Иванов Иван Иванович",
+		Status:     nopaper.CertificateStatusAvailable,
+		CustomData: nopaper.CertificateCustomData{PublicKey: base64.StdEncoding.EncodeToString(cert.RawSubjectPublicKeyInfo)},
+	}
+
+	act, sig := readFixture(t, "act.txt"), readFixture(t, "ec.p7s")
+
+	res, err := File(info, nopaper.FileInfoResponse{FileNameWithExtension: "act.txt", FileBase64: base64.StdEncoding.EncodeToString(act)}, sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res.OwnerID != info.OwnerID || res.OwnerName != info.OwnerName || res.CertificateID != info.ID || res.FileName != "act.txt" {
+		t.Errorf("File() = %+v, want owner of certificate %+v", res, info)
+	}
+
+	if _, err := Data(info, "act.txt", append(act, ' '), sig); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Data() of changed file error = %v, want %v", err, ErrInvalidSignature)
+	}
+}